@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -36,6 +37,28 @@ func main() {
 		}
 	}
 
+	// Apply extension remappings before any file is parsed
+	for ext, name := range cfg.Extensions {
+		if err := parser.RemapExtension(ext, name); err != nil {
+			fmt.Printf("Error in extensions setting: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	for _, plugin := range cfg.Plugins {
+		if err := parser.RegisterPlugin(plugin); err != nil {
+			fmt.Printf("Error in plugins setting: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if cfg.Queries != "" {
+		if err := parser.LoadQueries(cfg.Queries); err != nil {
+			fmt.Printf("Error in queries setting: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Create output directory
 	err = os.MkdirAll(*outputDir, 0755)
 	if err != nil {
@@ -74,34 +97,35 @@ func main() {
 func parseFiles(files []string) []types.FileMap {
 	var fileMaps []types.FileMap
 	for _, file := range files {
-		p := parser.GetParser(file)
-		if p == nil {
+		lang := parser.Lookup(file, nil)
+		if lang == nil {
 			continue // Unsupported file
 		}
 
-		defs, err := p.Parse(file)
-		if err != nil {
+		defs, err := lang.New().Parse(file)
+		// Files with syntax errors are kept with what could be parsed
+		var diagnostics []types.Diagnostic
+		var partial *parser.PartialError
+		if errors.As(err, &partial) {
+			fmt.Printf("Warning: %s parsed partially: %v\n", file, err)
+			diagnostics = partial.Diagnostics
+		} else if err != nil {
 			fmt.Printf("Error parsing %s: %v\n", file, err)
 			continue
 		}
 
-		lang := "unknown"
-		if filepath.Ext(file) == ".go" {
-			lang = "go"
-		} else if filepath.Ext(file) == ".js" {
-			lang = "javascript"
-		} else if filepath.Ext(file) == ".ts" {
-			lang = "typescript"
-		} else if filepath.Ext(file) == ".py" {
-			lang = "python"
-		}
-
 		fileMaps = append(fileMaps, types.FileMap{
 			Path:        file,
-			Language:    lang,
+			Language:    lang.Name,
+			Partial:     len(diagnostics) > 0,
+			Diagnostics: diagnostics,
 			Definitions: defs,
 		})
 	}
+	parser.LinkDeclarations(fileMaps)
+	parser.MergePartialTypes(fileMaps)
+	parser.LinkExtensions(fileMaps)
+	parser.MergeGoPackages(fileMaps)
 	return fileMaps
 }
 
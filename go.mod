@@ -2,9 +2,8 @@ module codemap
 
 go 1.25.5
 
-require gopkg.in/yaml.v2 v2.4.0
-
 require (
-	github.com/bmatcuk/doublestar/v4 v4.9.1 // indirect
-	github.com/smacker/go-tree-sitter v0.0.0-20240827094217-dd81d9e9be82 // indirect
+	github.com/bmatcuk/doublestar/v4 v4.9.1
+	github.com/smacker/go-tree-sitter v0.0.0-20240827094217-dd81d9e9be82
+	gopkg.in/yaml.v2 v2.4.0
 )
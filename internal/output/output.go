@@ -23,16 +23,111 @@ type CodemapXML struct {
 type FileXML struct {
 	Path        string       `xml:"path,attr"`
 	Language    string       `xml:"language,attr"`
+	Package     string       `xml:"package,attr,omitempty"`
+	Partial     bool         `xml:"partial,attr,omitempty"`
+	Diagnostics []DiagnosticXML `xml:"diagnostic"`
 	Definitions []DefinitionXML `xml:"definition"`
 }
 
-// DefinitionXML represents a definition in XML
+// DiagnosticXML represents a parse diagnostic of a file in XML
+type DiagnosticXML struct {
+	Severity string `xml:"severity,attr"`
+	Line     int    `xml:"line,attr"`
+	Message  string `xml:",chardata"`
+}
+
+// DefinitionXML represents a definition in XML; members are nested inside
+// their enclosing definition
 type DefinitionXML struct {
 	Type      string `xml:"type,attr"`
 	Name      string `xml:"name,attr"`
 	Line       int    `xml:"line,attr"`
-	Signature string `xml:",innerxml"`
+	Parent    string `xml:"parent,attr,omitempty"`
+	QualifiedName string `xml:"qualified_name,attr,omitempty"`
+	Tag       string `xml:"tag,attr,omitempty"`
+	Modifiers string `xml:"modifiers,attr,omitempty"`
+	Export    string `xml:"export,attr,omitempty"`
+	Props     string `xml:"props,attr,omitempty"`
+	Hooks     string `xml:"hooks,attr,omitempty"`
+	Emits     string `xml:"emits,attr,omitempty"`
+	Slots     string `xml:"slots,attr,omitempty"`
+	Deprecated string `xml:"deprecated,attr,omitempty"`
+	Build     string `xml:"build,attr,omitempty"`
+	Files     string `xml:"files,attr,omitempty"`
+	Signature string `xml:"signature,omitempty"` // Signature, or definition of types
 	Comment   string `xml:"comment,omitempty"`
+	Annotations []string `xml:"annotation"`
+	Params    []DocParamXML `xml:"param"`
+	Returns   *DocParamXML  `xml:"returns"`
+	Raises    []DocParamXML `xml:"raises"`
+	Examples  []string      `xml:"example"`
+	TypeParams []TypeParamXML `xml:"type_param"`
+	Children  []DefinitionXML `xml:"definition"`
+}
+
+// TypeParamXML represents a type parameter and its constraint in XML
+type TypeParamXML struct {
+	Name       string `xml:"name,attr"`
+	Constraint string `xml:"constraint,attr,omitempty"`
+}
+
+// DocParamXML represents a documented parameter, return value or exception in XML
+type DocParamXML struct {
+	Name        string `xml:"name,attr,omitempty"`
+	Type        string `xml:"type,attr,omitempty"`
+	Description string `xml:",chardata"`
+}
+
+// FileJSON represents a file in JSON with its definitions nested by scope
+type FileJSON struct {
+	Path        string
+	Language    string
+	Package     string `json:",omitempty"`
+	Partial     bool   `json:",omitempty"`
+	Diagnostics []types.Diagnostic `json:",omitempty"`
+	Definitions []DefinitionJSON
+}
+
+// DefinitionJSON is a definition with its members nested below it
+type DefinitionJSON struct {
+	types.Definition
+	Children []DefinitionJSON `json:"children,omitempty"`
+}
+
+// defNode is a definition together with the definitions it encloses
+type defNode struct {
+	def      types.Definition
+	children []defNode
+}
+
+// nestDefinitions arranges a file's flat definition list into a tree using
+// each definition's ParentId. Definitions whose parent is not in the list
+// stay at the top level.
+func nestDefinitions(defs []types.Definition) []defNode {
+	index := make(map[string]int)
+	for i, d := range defs {
+		if _, ok := index[d.Id]; !ok {
+			index[d.Id] = i
+		}
+	}
+	children := make(map[int][]int)
+	var roots []int
+	for i, d := range defs {
+		if p, ok := index[d.ParentId]; ok && d.ParentId != "" && p != i {
+			children[p] = append(children[p], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+	var build func(ids []int) []defNode
+	build = func(ids []int) []defNode {
+		var nodes []defNode
+		for _, i := range ids {
+			nodes = append(nodes, defNode{def: defs[i], children: build(children[i])})
+		}
+		return nodes
+	}
+	return build(roots)
 }
 
 // GenerateXML converts file maps to XML string
@@ -42,20 +137,14 @@ func GenerateXML(files []types.FileMap) (string, error) {
 		fileXML := FileXML{
 			Path:     f.Path,
 			Language: f.Language,
+			Package:  f.Package,
+			Partial:  f.Partial,
+		}
+		for _, d := range f.Diagnostics {
+			fileXML.Diagnostics = append(fileXML.Diagnostics, DiagnosticXML{Severity: d.Severity, Line: d.Line, Message: d.Message})
 		}
-		for _, d := range f.Definitions {
-			content := d.Signature
-			if d.Type == "type" {
-				content = d.Definition
-			}
-			defXML := DefinitionXML{
-				Type:      d.Type,
-				Name:      d.Name,
-				Line:       d.Line,
-				Signature: content,
-				Comment:   d.Comment,
-			}
-			fileXML.Definitions = append(fileXML.Definitions, defXML)
+		for _, n := range nestDefinitions(f.Definitions) {
+			fileXML.Definitions = append(fileXML.Definitions, definitionXML(n))
 		}
 		codemap.Files = append(codemap.Files, fileXML)
 	}
@@ -64,12 +153,83 @@ func GenerateXML(files []types.FileMap) (string, error) {
 	return string(data), err
 }
 
+// definitionXML converts a definition and its members to their XML form
+func definitionXML(n defNode) DefinitionXML {
+	d := n.def
+	content := d.Signature
+	if d.Definition != "" {
+		content = d.Definition
+	}
+	defXML := DefinitionXML{
+		Type:      d.Type,
+		Name:      d.Name,
+		Line:       d.Line,
+		Parent:    d.Parent,
+		QualifiedName: d.QualifiedName,
+		Tag:       d.Tag,
+		Modifiers: strings.Join(d.Modifiers, " "),
+		Export:    d.Export,
+		Props:     d.Props,
+		Hooks:     strings.Join(d.Hooks, " "),
+		Emits:     strings.Join(d.Emits, " "),
+		Slots:     strings.Join(d.Slots, " "),
+		Deprecated: d.Deprecated,
+		Build:     d.Build,
+		Files:     strings.Join(d.Files, " "),
+		Signature: content,
+		Comment:   d.Comment,
+		Annotations: d.Annotations,
+		Params:    docParamsXML(d.Params),
+		Raises:    docParamsXML(d.Raises),
+		Examples:  d.Examples,
+	}
+	for _, tp := range d.TypeParams {
+		defXML.TypeParams = append(defXML.TypeParams, TypeParamXML{Name: tp.Name, Constraint: tp.Constraint})
+	}
+	if d.Returns != nil {
+		defXML.Returns = &docParamsXML([]types.DocParam{*d.Returns})[0]
+	}
+	for _, c := range n.children {
+		defXML.Children = append(defXML.Children, definitionXML(c))
+	}
+	return defXML
+}
+
+// docParamsXML converts structured doc entries to their XML form
+func docParamsXML(params []types.DocParam) []DocParamXML {
+	var out []DocParamXML
+	for _, p := range params {
+		out = append(out, DocParamXML{Name: p.Name, Type: p.Type, Description: p.Description})
+	}
+	return out
+}
+
 // GenerateJSON converts file maps to JSON string
 func GenerateJSON(files []types.FileMap) (string, error) {
-	data, err := json.MarshalIndent(files, "", "  ")
+	out := make([]FileJSON, 0, len(files))
+	for _, f := range files {
+		out = append(out, FileJSON{
+			Path:        f.Path,
+			Language:    f.Language,
+			Package:     f.Package,
+			Partial:     f.Partial,
+			Diagnostics: f.Diagnostics,
+			Definitions: definitionsJSON(nestDefinitions(f.Definitions)),
+		})
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
 	return string(data), err
 }
 
+// definitionsJSON converts definition trees to their JSON form
+func definitionsJSON(nodes []defNode) []DefinitionJSON {
+	var out []DefinitionJSON
+	for _, n := range nodes {
+		out = append(out, DefinitionJSON{Definition: n.def, Children: definitionsJSON(n.children)})
+	}
+	return out
+}
+
 // GenerateYAML converts file maps to YAML string
 func GenerateYAML(files []types.FileMap) (string, error) {
 	data, err := yaml.Marshal(files)
@@ -97,7 +257,11 @@ func buildSearchableText(def types.Definition, filePath, language string) string
 		tokens = append(tokens, dir)
 	}
 
-	// 3. Parent scope - skipped, no receiver info yet
+	// 3. Parent scope
+	if def.Parent != "" {
+		tokens = append(tokens, strings.ToLower(def.Parent))
+		tokens = append(tokens, strings.ToLower(def.QualifiedName))
+	}
 
 	// 4. Documentation
 	if def.Comment != "" {
@@ -122,6 +286,30 @@ func buildSearchableText(def types.Definition, filePath, language string) string
 		tokens = append(tokens, "javascript", "js")
 	case "typescript":
 		tokens = append(tokens, "typescript", "ts")
+	case "python":
+		tokens = append(tokens, "python", "py")
+	case "rust":
+		tokens = append(tokens, "rust", "rs")
+	case "java":
+		tokens = append(tokens, "java")
+	case "ruby":
+		tokens = append(tokens, "ruby", "rb")
+	case "php":
+		tokens = append(tokens, "php")
+	case "kotlin":
+		tokens = append(tokens, "kotlin", "kt")
+	case "swift":
+		tokens = append(tokens, "swift")
+	case "vue":
+		tokens = append(tokens, "vue")
+	case "svelte":
+		tokens = append(tokens, "svelte")
+	case "csharp":
+		tokens = append(tokens, "csharp", "c#")
+	case "c":
+		tokens = append(tokens, "c")
+	case "cpp":
+		tokens = append(tokens, "cpp", "c++")
 	}
 
 	// 7. Role/intent tags
@@ -149,7 +337,13 @@ func buildSearchableText(def types.Definition, filePath, language string) string
 	if containsAny(nameLower, []string{"handle", "route", "endpoint", "http"}) {
 		tokens = append(tokens, "http-handler", "route", "endpoint")
 	}
-	if unicode.IsUpper(rune(def.Name[0])) {
+	if def.Type == "component" {
+		tokens = append(tokens, "react", "component", "ui")
+	}
+	if def.Deprecated != "" {
+		tokens = append(tokens, "deprecated", "legacy")
+	}
+	if unicode.IsUpper(rune(def.Name[0])) || def.Export != "" {
 		tokens = append(tokens, "public", "api", "exported")
 	}
 
@@ -176,6 +370,22 @@ func buildSearchableText(def types.Definition, filePath, language string) string
 func GenerateJSONL(files []types.FileMap) (string, error) {
 	var lines []string
 	for _, file := range files {
+		// Diagnostics get a record of their own, so a file that yielded no
+		// definitions still appears in the map
+		if len(file.Diagnostics) > 0 {
+			obj := map[string]interface{}{
+				"file":        file.Path,
+				"language":    file.Language,
+				"type":        "diagnostics",
+				"partial":     file.Partial,
+				"diagnostics": file.Diagnostics,
+			}
+			data, err := json.Marshal(obj)
+			if err != nil {
+				return "", err
+			}
+			lines = append(lines, string(data))
+		}
 		for _, def := range file.Definitions {
 			obj := map[string]interface{}{
 				"file":       file.Path,
@@ -185,6 +395,12 @@ func GenerateJSONL(files []types.FileMap) (string, error) {
 				"line_start": def.Line,
 				"id":         def.Id,
 			}
+			if file.Package != "" {
+				obj["package"] = file.Package
+			}
+			if file.Partial {
+				obj["partial"] = true
+			}
 			if def.LineEnd != 0 {
 				obj["line_end"] = def.LineEnd
 			}
@@ -197,6 +413,69 @@ func GenerateJSONL(files []types.FileMap) (string, error) {
 			if def.Comment != "" {
 				obj["doc"] = def.Comment
 			}
+			if def.Tag != "" {
+				obj["tag"] = def.Tag
+			}
+			if len(def.Modifiers) > 0 {
+				obj["modifiers"] = def.Modifiers
+			}
+			if len(def.Annotations) > 0 {
+				obj["annotations"] = def.Annotations
+			}
+			if def.Parent != "" {
+				obj["parent"] = def.Parent
+			}
+			if def.ParentId != "" {
+				obj["parent_id"] = def.ParentId
+			}
+			if def.QualifiedName != "" {
+				obj["qualified_name"] = def.QualifiedName
+			}
+			if def.DeclarationId != "" {
+				obj["declaration_id"] = def.DeclarationId
+			}
+			if def.ImplementationId != "" {
+				obj["implementation_id"] = def.ImplementationId
+			}
+			if def.Export != "" {
+				obj["export"] = def.Export
+			}
+			if def.Props != "" {
+				obj["props"] = def.Props
+			}
+			if len(def.Hooks) > 0 {
+				obj["hooks"] = def.Hooks
+			}
+			if len(def.Emits) > 0 {
+				obj["emits"] = def.Emits
+			}
+			if len(def.Slots) > 0 {
+				obj["slots"] = def.Slots
+			}
+			if len(def.Params) > 0 {
+				obj["params"] = def.Params
+			}
+			if def.Returns != nil {
+				obj["returns"] = def.Returns
+			}
+			if len(def.Raises) > 0 {
+				obj["raises"] = def.Raises
+			}
+			if def.Deprecated != "" {
+				obj["deprecated"] = def.Deprecated
+			}
+			if len(def.Examples) > 0 {
+				obj["examples"] = def.Examples
+			}
+			if len(def.TypeParams) > 0 {
+				obj["type_params"] = def.TypeParams
+			}
+			if def.Build != "" {
+				obj["build"] = def.Build
+			}
+			if len(def.Files) > 0 {
+				obj["files"] = def.Files
+			}
 			obj["searchable_text"] = buildSearchableText(def, file.Path, file.Language)
 			data, err := json.Marshal(obj)
 			if err != nil {
@@ -0,0 +1,481 @@
+package parser
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/c"
+	"github.com/smacker/go-tree-sitter/cpp"
+
+	"codemap/internal/types"
+)
+
+// CParser implements the Parser interface for C and C++ files. Function
+// declarations without a body are emitted as "prototype" definitions and
+// are linked to their implementations by LinkDeclarations.
+type CParser struct {
+	// CPlusPlus selects the C++ grammar, which also accepts most C headers
+	CPlusPlus bool
+}
+
+// Parse reads filePath and extracts its definitions with ParseContent
+func (p *CParser) Parse(filePath string) ([]types.Definition, error) {
+	return parseFile(p, filePath)
+}
+
+// ParseContent extracts definitions from C/C++ source using AST parsing
+func (p *CParser) ParseContent(ctx context.Context, filePath string, src []byte) ([]types.Definition, error) {
+	lang := c.GetLanguage()
+	if p.CPlusPlus {
+		lang = cpp.GetLanguage()
+	}
+
+	parser := sitter.NewParser()
+	parser.SetLanguage(lang)
+	tree, err := parser.ParseCtx(ctx, nil, src)
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []types.Definition
+
+	walkCTree(tree.RootNode(), src, filePath, nil, &definitions)
+
+	// Link out-of-line members such as Shape::area to their class when it
+	// is declared in this file
+	typeIds := make(map[string]string)
+	for _, def := range definitions {
+		switch def.Type {
+		case "class", "struct", "union", "namespace":
+			typeIds[qualifiedNameOf(def)] = def.Id
+		}
+	}
+	for i := range definitions {
+		if definitions[i].ParentId == "" && definitions[i].Parent != "" {
+			definitions[i].ParentId = typeIds[definitions[i].Parent]
+			if definitions[i].ParentId != "" && definitions[i].Type == "function" {
+				definitions[i].Type = "method"
+			}
+		}
+	}
+
+	return definitions, syntaxErrors(tree.RootNode(), src)
+}
+
+func walkCTree(node *sitter.Node, src []byte, filePath string, parent *types.Definition, definitions *[]types.Definition) {
+	var def *types.Definition
+	switch node.Type() {
+	case "function_definition":
+		if node.ChildByFieldName("body") == nil {
+			// Pure virtual and deleted functions have no body
+			def = extractCFunction(node, "prototype", src, filePath, parent)
+		} else {
+			def = extractCFunction(node, "function", src, filePath, parent)
+		}
+		if def != nil {
+			*definitions = append(*definitions, *def)
+		}
+		// Function bodies hold no definitions worth mapping
+		return
+	case "declaration", "field_declaration":
+		if def := extractCFunction(node, "prototype", src, filePath, parent); def != nil {
+			*definitions = append(*definitions, *def)
+			return
+		}
+	case "struct_specifier":
+		def = extractCType(node, "struct", src, filePath)
+	case "union_specifier":
+		def = extractCType(node, "union", src, filePath)
+	case "enum_specifier":
+		def = extractCType(node, "enum", src, filePath)
+	case "class_specifier":
+		def = extractCType(node, "class", src, filePath)
+	case "namespace_definition":
+		def = extractCType(node, "namespace", src, filePath)
+	case "type_definition", "alias_declaration":
+		def = extractCTypedef(node, src, filePath)
+	case "preproc_def", "preproc_function_def":
+		def = extractCMacro(node, src, filePath)
+	}
+	if def != nil {
+		linkParent(def, parent)
+		*definitions = append(*definitions, *def)
+		parent = def
+	}
+
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		walkCTree(child, src, filePath, parent, definitions)
+	}
+}
+
+// extractCFunction builds a definition for a function definition or
+// prototype. It returns nil for declarations that do not declare a
+// function, such as variables and function pointers.
+func extractCFunction(node *sitter.Node, defType string, src []byte, filePath string, parent *types.Definition) *types.Definition {
+	declarator := findFunctionDeclarator(node.ChildByFieldName("declarator"))
+	if declarator == nil {
+		return nil
+	}
+	nameNode := declarator.ChildByFieldName("declarator")
+	if nameNode == nil || nameNode.Type() == "parenthesized_declarator" {
+		return nil
+	}
+
+	// Qualified declarators such as geo::Shape::area name their own scope
+	var scope []string
+	for nameNode.Type() == "qualified_identifier" {
+		if s := nameNode.ChildByFieldName("scope"); s != nil {
+			scope = append(scope, s.Content(src))
+		}
+		next := nameNode.ChildByFieldName("name")
+		if next == nil {
+			break
+		}
+		nameNode = next
+	}
+	name := nameNode.Content(src)
+	if nameNode.Type() == "template_function" {
+		if n := nameNode.ChildByFieldName("name"); n != nil {
+			name = n.Content(src)
+		}
+	}
+
+	outer := cTemplateWrapper(node)
+	sig := strings.TrimSpace(string(src[outer.StartByte():node.EndByte()]))
+	if body := node.ChildByFieldName("body"); body != nil {
+		sig = strings.TrimSpace(string(src[outer.StartByte():body.StartByte()]))
+	}
+	sig = normalizeWhitespace(strings.TrimSuffix(sig, ";"))
+
+	if parent != nil && (parent.Type == "class" || parent.Type == "struct" || parent.Type == "union") && defType == "function" {
+		defType = "method"
+	}
+
+	def := types.Definition{
+		Type:      defType,
+		Name:      name,
+		Line:      int(outer.StartPoint().Row) + 1,
+		LineEnd:   int(node.EndPoint().Row) + 1,
+		Signature: sig,
+		Comment:   extractCComment(outer, src),
+		Modifiers: cModifiers(node, src),
+	}
+	linkParent(&def, parent)
+	if len(scope) > 0 {
+		prefix := def.Parent
+		if prefix != "" {
+			prefix += "."
+		}
+		def.Parent = prefix + strings.Join(scope, ".")
+		def.ParentId = ""
+		def.QualifiedName = def.Parent + "." + name
+	}
+	def.Id = computeId(filePath, qualifiedNameOf(def), sig)
+	return &def
+}
+
+// findFunctionDeclarator descends through pointer and reference declarators
+// to the function declarator, if any
+func findFunctionDeclarator(node *sitter.Node) *sitter.Node {
+	for node != nil {
+		switch node.Type() {
+		case "function_declarator":
+			return node
+		case "pointer_declarator", "reference_declarator":
+			next := node.ChildByFieldName("declarator")
+			if next == nil && node.NamedChildCount() > 0 {
+				next = node.NamedChild(int(node.NamedChildCount()) - 1)
+			}
+			node = next
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// extractCType builds a definition for a struct, union, enum, class or
+// namespace. Only specifiers with a body are definitions; the rest merely
+// refer to the type. Classes and namespaces are reduced to their header.
+func extractCType(node *sitter.Node, defType string, src []byte, filePath string) *types.Definition {
+	nameNode := node.ChildByFieldName("name")
+	body := node.ChildByFieldName("body")
+	if nameNode == nil || body == nil {
+		return nil
+	}
+	name := nameNode.Content(src)
+	outer := cTemplateWrapper(node)
+
+	var definition string
+	switch defType {
+	case "class", "namespace":
+		definition = strings.TrimSpace(string(src[outer.StartByte():body.StartByte()]))
+	default:
+		definition = string(src[outer.StartByte():node.EndByte()])
+	}
+	definition = normalizeWhitespace(definition)
+
+	def := types.Definition{
+		Type:       defType,
+		Name:       name,
+		Line:       int(outer.StartPoint().Row) + 1,
+		LineEnd:    int(node.EndPoint().Row) + 1,
+		Id:         computeId(filePath, name, definition),
+		Definition: definition,
+		Comment:    extractCComment(outer, src),
+	}
+	return &def
+}
+
+// extractCTypedef builds a definition for a typedef or C++ using alias
+func extractCTypedef(node *sitter.Node, src []byte, filePath string) *types.Definition {
+	var name string
+	if node.Type() == "alias_declaration" {
+		if n := node.ChildByFieldName("name"); n != nil {
+			name = n.Content(src)
+		}
+	} else {
+		name = cDeclaratorName(node.ChildByFieldName("declarator"), src)
+	}
+	if name == "" {
+		return nil
+	}
+	definition := normalizeWhitespace(node.Content(src))
+	def := types.Definition{
+		Type:       "typedef",
+		Name:       name,
+		Line:       int(node.StartPoint().Row) + 1,
+		LineEnd:    int(node.EndPoint().Row) + 1,
+		Id:         computeId(filePath, name, definition),
+		Definition: definition,
+		Comment:    extractCComment(node, src),
+	}
+	return &def
+}
+
+// cDeclaratorName returns the identifier a declarator introduces
+func cDeclaratorName(node *sitter.Node, src []byte) string {
+	for node != nil {
+		switch node.Type() {
+		case "type_identifier", "identifier", "primitive_type":
+			return node.Content(src)
+		}
+		next := node.ChildByFieldName("declarator")
+		if next == nil && node.NamedChildCount() > 0 {
+			next = node.NamedChild(0)
+		}
+		node = next
+	}
+	return ""
+}
+
+// extractCMacro builds a definition for a #define. Value-less defines that
+// only serve as include guards are skipped.
+func extractCMacro(node *sitter.Node, src []byte, filePath string) *types.Definition {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil
+	}
+	name := nameNode.Content(src)
+	if node.ChildByFieldName("value") == nil && node.Type() == "preproc_def" {
+		if guard := node.Parent(); guard != nil && guard.Type() == "preproc_ifdef" {
+			if n := guard.ChildByFieldName("name"); n != nil && n.Content(src) == name {
+				return nil
+			}
+		}
+	}
+	definition := normalizeWhitespace(strings.TrimSpace(node.Content(src)))
+	// The directive includes its newline, so it ends at the start of the
+	// following line
+	end := node.EndPoint()
+	lineEnd := int(end.Row) + 1
+	if end.Column == 0 && end.Row > node.StartPoint().Row {
+		lineEnd--
+	}
+	def := types.Definition{
+		Type:       "macro",
+		Name:       name,
+		Line:       int(node.StartPoint().Row) + 1,
+		LineEnd:    lineEnd,
+		Id:         computeId(filePath, name, definition),
+		Definition: definition,
+		Comment:    extractCComment(node, src),
+	}
+	return &def
+}
+
+// cTemplateWrapper returns the template declaration wrapping node, or node
+// itself, so template parameters are part of the definition
+func cTemplateWrapper(node *sitter.Node) *sitter.Node {
+	if parent := node.Parent(); parent != nil && parent.Type() == "template_declaration" {
+		return parent
+	}
+	return node
+}
+
+// cModifiers returns the storage class and virtual specifiers of a function
+func cModifiers(node *sitter.Node, src []byte) []string {
+	var modifiers []string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		switch child.Type() {
+		case "storage_class_specifier", "virtual", "virtual_specifier":
+			modifiers = append(modifiers, child.Content(src))
+		}
+	}
+	return modifiers
+}
+
+// extractCComment collects the comments directly above node, accepting
+// both // lines and /* */ blocks
+func extractCComment(node *sitter.Node, src []byte) string {
+	var comments []string
+	row := node.StartPoint().Row
+	for prev := node.PrevNamedSibling(); prev != nil && prev.Type() == "comment"; prev = prev.PrevNamedSibling() {
+		if prev.EndPoint().Row+1 < row {
+			break
+		}
+		comments = append([]string{cleanCComment(prev.Content(src))}, comments...)
+		row = prev.StartPoint().Row
+	}
+	return strings.Join(comments, "\n")
+}
+
+// cleanCComment strips comment markers from a C/C++ comment
+func cleanCComment(text string) string {
+	if strings.HasPrefix(text, "/*") {
+		return cleanDocBlock("/**" + strings.TrimPrefix(strings.TrimPrefix(text, "/*"), "*"))
+	}
+	return strings.TrimSpace(strings.TrimLeft(text, "/!"))
+}
+
+// LinkDeclarations pairs C/C++ prototypes with the functions that implement
+// them, across all C and C++ files, by qualified name and parameter types,
+// so each overload finds its own prototype. A linked implementation of a
+// class member becomes a method.
+func LinkDeclarations(files []types.FileMap) {
+	type ref struct{ file, def int }
+	prototypes := make(map[string][]ref)
+	for fi, f := range files {
+		if !isCFile(f) {
+			continue
+		}
+		for di, d := range f.Definitions {
+			if d.Type == "prototype" {
+				key := cDeclarationKey(d)
+				prototypes[key] = append(prototypes[key], ref{fi, di})
+			}
+		}
+	}
+	for fi := range files {
+		if !isCFile(files[fi]) {
+			continue
+		}
+		for di := range files[fi].Definitions {
+			def := &files[fi].Definitions[di]
+			if def.Type != "function" && def.Type != "method" {
+				continue
+			}
+			key := cDeclarationKey(*def)
+			candidates := prototypes[key]
+			if len(candidates) == 0 {
+				continue
+			}
+			decl := &files[candidates[0].file].Definitions[candidates[0].def]
+			prototypes[key] = candidates[1:]
+			def.DeclarationId = decl.Id
+			decl.ImplementationId = def.Id
+			if decl.ParentId != "" {
+				def.Type = "method"
+			}
+		}
+	}
+}
+
+// cDeclarationKey identifies a function by its qualified name and the types
+// of its parameters, e.g. Shape.over(int,const char*)
+func cDeclarationKey(def types.Definition) string {
+	return qualifiedNameOf(def) + "(" + strings.Join(cParamTypes(def.Signature), ",") + ")"
+}
+
+var cTypeSpacing = regexp.MustCompile(`\s*([*&\[\](),<>:])\s*`)
+
+// cParamTypes returns the parameter types of a C/C++ function signature,
+// without parameter names or default values. An empty C parameter list
+// written as (void) has no types.
+func cParamTypes(sig string) []string {
+	src := []byte(sig + ";")
+	parser := sitter.NewParser()
+	parser.SetLanguage(cpp.GetLanguage())
+	tree, err := parser.ParseCtx(context.Background(), nil, src)
+	if err != nil {
+		return nil
+	}
+	declarator := findNodeOfType(tree.RootNode(), "function_declarator")
+	if declarator == nil {
+		return nil
+	}
+	params := declarator.ChildByFieldName("parameters")
+	if params == nil {
+		return nil
+	}
+
+	var paramTypes []string
+	for i := 0; i < int(params.ChildCount()); i++ {
+		// Variadic "..." is an unnamed child, kept as a type of its own
+		param := params.Child(i)
+		switch param.Type() {
+		case "(", ")", ",", "comment":
+			continue
+		}
+		start, end := param.StartByte(), param.EndByte()
+		if value := param.ChildByFieldName("default_value"); value != nil {
+			end = value.StartByte()
+		}
+		text := string(src[start:end])
+		if name := cParamName(param); name != nil {
+			text = string(src[start:name.StartByte()]) + string(src[name.EndByte():end])
+		}
+		text = strings.TrimSuffix(strings.TrimSpace(text), "=")
+		paramTypes = append(paramTypes, cTypeSpacing.ReplaceAllString(normalizeWhitespace(strings.TrimSpace(text)), "$1"))
+	}
+	if len(paramTypes) == 1 && paramTypes[0] == "void" {
+		return nil
+	}
+	return paramTypes
+}
+
+// cParamName returns the identifier a parameter declarator names, if any
+func cParamName(param *sitter.Node) *sitter.Node {
+	node := param.ChildByFieldName("declarator")
+	for node != nil && node.Type() != "identifier" {
+		next := node.ChildByFieldName("declarator")
+		if next == nil && node.NamedChildCount() > 0 {
+			next = node.NamedChild(0)
+		}
+		node = next
+	}
+	return node
+}
+
+// findNodeOfType returns the first node of the given type in a depth-first
+// walk of node
+func findNodeOfType(node *sitter.Node, nodeType string) *sitter.Node {
+	if node.Type() == nodeType {
+		return node
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		if found := findNodeOfType(node.NamedChild(i), nodeType); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// isCFile reports whether a file was parsed as C or C++
+func isCFile(f types.FileMap) bool {
+	return f.Language == "c" || f.Language == "cpp"
+}
@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"codemap/internal/types"
+)
+
+func TestCParser_Parse(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "shape.hpp")
+	testContent := `#ifndef SHAPE_HPP
+#define SHAPE_HPP
+
+#define MAX(a, b) \
+    ((a) > (b) ? (a) : (b))
+
+/* A point */
+struct point { int x; int y; };
+
+typedef struct point point_t;
+
+// Adds numbers
+int add(int a, int b);
+
+namespace geo {
+/** A shape */
+class Shape {
+public:
+    Shape(int x);
+    virtual double area() const = 0;
+};
+
+template <typename T> T max(T a, T b) { return a > b ? a : b; }
+}
+
+#endif
+`
+	err := os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	parser := &CParser{CPlusPlus: true}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []struct {
+		name   string
+		typ    string
+		parent string
+	}{
+		{"MAX", "macro", ""},
+		{"point", "struct", ""},
+		{"point_t", "typedef", ""},
+		{"add", "prototype", ""},
+		{"geo", "namespace", ""},
+		{"Shape", "class", "geo"},
+		{"Shape", "prototype", "geo.Shape"},
+		{"area", "prototype", "geo.Shape"},
+		{"max", "function", "geo"},
+	}
+
+	if len(definitions) != len(expected) {
+		t.Fatalf("Expected %d definitions, got %d", len(expected), len(definitions))
+	}
+
+	for i, exp := range expected {
+		if definitions[i].Name != exp.name {
+			t.Errorf("Definition %d: expected name %s, got %s", i, exp.name, definitions[i].Name)
+		}
+		if definitions[i].Type != exp.typ {
+			t.Errorf("Definition %d: expected type %s, got %s", i, exp.typ, definitions[i].Type)
+		}
+		if definitions[i].Parent != exp.parent {
+			t.Errorf("Definition %d: expected parent %q, got %q", i, exp.parent, definitions[i].Parent)
+		}
+	}
+
+	if definitions[1].Comment != "A point" || definitions[3].Comment != "Adds numbers" || definitions[5].Comment != "A shape" {
+		t.Errorf("Expected comments to be extracted")
+	}
+	if definitions[0].Line != 4 || definitions[0].LineEnd != 5 {
+		t.Errorf("Expected MAX to span lines 4-5, got %d-%d", definitions[0].Line, definitions[0].LineEnd)
+	}
+	if want := "template <typename T> T max(T a, T b)"; definitions[8].Signature != want {
+		t.Errorf("Expected signature %q, got %q", want, definitions[8].Signature)
+	}
+}
+
+func TestLinkDeclarations(t *testing.T) {
+	tempDir := t.TempDir()
+
+	header := filepath.Join(tempDir, "shape.h")
+	source := filepath.Join(tempDir, "shape.cpp")
+	os.WriteFile(header, []byte("int add(int a, int b);\nclass Shape {\n    double area();\n};\n"), 0644)
+	os.WriteFile(source, []byte("int add(int a, int b) { return a + b; }\ndouble Shape::area() { return 0; }\n"), 0644)
+
+	parser := &CParser{CPlusPlus: true}
+	var files []types.FileMap
+	for _, path := range []string{header, source} {
+		defs, err := parser.Parse(path)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		files = append(files, types.FileMap{Path: path, Language: "cpp", Definitions: defs})
+	}
+	// Functions of other languages are never linked to C prototypes
+	files = append(files, types.FileMap{Path: "add.go", Language: "go", Definitions: []types.Definition{{Type: "function", Name: "add", Id: "go-add"}}})
+
+	LinkDeclarations(files)
+
+	decls, impls := files[0].Definitions, files[1].Definitions
+	if impls[0].DeclarationId != decls[0].Id || decls[0].ImplementationId != impls[0].Id {
+		t.Errorf("Expected add to be linked to its prototype")
+	}
+	if impls[1].DeclarationId != decls[2].Id || decls[2].ImplementationId != impls[1].Id {
+		t.Errorf("Expected Shape::area to be linked to its prototype")
+	}
+	if impls[1].Type != "method" {
+		t.Errorf("Expected Shape::area to be a method, got %s", impls[1].Type)
+	}
+	if files[2].Definitions[0].DeclarationId != "" {
+		t.Errorf("Expected the Go function not to be linked, got %q", files[2].Definitions[0].DeclarationId)
+	}
+}
+
+func TestLinkDeclarations_Overloads(t *testing.T) {
+	tempDir := t.TempDir()
+
+	header := filepath.Join(tempDir, "shape.h")
+	source := filepath.Join(tempDir, "shape.cpp")
+	os.WriteFile(header, []byte("class Shape {\n    void over(int x);\n    void over(double x, const char *label = 0);\n    void scale(int);\n};\n"), 0644)
+	// Definitions come in another order than their prototypes, and scale
+	// has no prototype taking a float
+	os.WriteFile(source, []byte("void Shape::over(double d, const char* name) {}\nvoid Shape::over(int n) {}\nvoid Shape::scale(float f) {}\n"), 0644)
+
+	parser := &CParser{CPlusPlus: true}
+	var files []types.FileMap
+	for _, path := range []string{header, source} {
+		defs, err := parser.Parse(path)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		files = append(files, types.FileMap{Path: path, Language: "cpp", Definitions: defs})
+	}
+
+	LinkDeclarations(files)
+
+	decls, impls := files[0].Definitions, files[1].Definitions
+	if impls[0].DeclarationId != decls[2].Id || decls[2].ImplementationId != impls[0].Id {
+		t.Errorf("Expected over(double, const char*) to be linked to its own prototype")
+	}
+	if impls[1].DeclarationId != decls[1].Id || decls[1].ImplementationId != impls[1].Id {
+		t.Errorf("Expected over(int) to be linked to its own prototype")
+	}
+	if impls[2].DeclarationId != "" || decls[3].ImplementationId != "" {
+		t.Errorf("Expected scale(float) to stay unlinked, got %q", impls[2].DeclarationId)
+	}
+}
@@ -0,0 +1,397 @@
+package parser
+
+import (
+	"context"
+	"encoding/xml"
+	"html"
+	"regexp"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/csharp"
+
+	"codemap/internal/types"
+)
+
+// CSharpParser implements the Parser interface for C# files. Parts of a
+// partial type declared in several files are merged by MergePartialTypes.
+type CSharpParser struct{}
+
+// Parse reads filePath and extracts its definitions with ParseContent
+func (p *CSharpParser) Parse(filePath string) ([]types.Definition, error) {
+	return parseFile(p, filePath)
+}
+
+// ParseContent extracts definitions from C# source using AST parsing
+func (p *CSharpParser) ParseContent(ctx context.Context, filePath string, src []byte) ([]types.Definition, error) {
+	parser := sitter.NewParser()
+	parser.SetLanguage(csharp.GetLanguage())
+	tree, err := parser.ParseCtx(ctx, nil, src)
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []types.Definition
+
+	walkCSharpTree(tree.RootNode(), src, filePath, nil, &definitions)
+
+	return definitions, syntaxErrors(tree.RootNode(), src)
+}
+
+func walkCSharpTree(node *sitter.Node, src []byte, filePath string, parent *types.Definition, definitions *[]types.Definition) {
+	var def *types.Definition
+	switch node.Type() {
+	case "namespace_declaration":
+		def = extractCSharpDeclaration(node, "namespace", src, filePath)
+	case "class_declaration":
+		def = extractCSharpDeclaration(node, "class", src, filePath)
+	case "interface_declaration":
+		def = extractCSharpDeclaration(node, "interface", src, filePath)
+	case "struct_declaration":
+		def = extractCSharpDeclaration(node, "struct", src, filePath)
+	case "record_declaration", "record_struct_declaration":
+		def = extractCSharpDeclaration(node, "record", src, filePath)
+	case "enum_declaration":
+		def = extractCSharpDeclaration(node, "enum", src, filePath)
+	case "method_declaration":
+		def = extractCSharpDeclaration(node, "method", src, filePath)
+	case "constructor_declaration":
+		def = extractCSharpDeclaration(node, "constructor", src, filePath)
+	case "property_declaration":
+		def = extractCSharpDeclaration(node, "property", src, filePath)
+	case "event_declaration":
+		def = extractCSharpDeclaration(node, "event", src, filePath)
+	case "event_field_declaration":
+		// A single declaration may introduce several events
+		for _, event := range extractCSharpEventFields(node, src, filePath) {
+			linkParent(&event, parent)
+			*definitions = append(*definitions, event)
+		}
+		return
+	}
+	if def != nil {
+		linkParent(def, parent)
+		*definitions = append(*definitions, *def)
+		parent = def
+	}
+
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		if child.Type() == "file_scoped_namespace_declaration" {
+			// A file-scoped namespace encloses every declaration after it
+			if ns := extractCSharpDeclaration(child, "namespace", src, filePath); ns != nil {
+				linkParent(ns, parent)
+				*definitions = append(*definitions, *ns)
+				parent = ns
+			}
+			continue
+		}
+		walkCSharpTree(child, src, filePath, parent, definitions)
+	}
+}
+
+// extractCSharpDeclaration builds a definition for a type or member
+// declaration. Types and namespaces are reduced to their header since
+// members are emitted separately; enums keep their members and properties
+// and events keep their accessors.
+func extractCSharpDeclaration(node *sitter.Node, defType string, src []byte, filePath string) *types.Definition {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil
+	}
+	name := nameNode.Content(src)
+
+	var header string
+	if accessors := node.ChildByFieldName("accessors"); accessors != nil {
+		header = strings.TrimSpace(string(src[node.StartByte():accessors.StartByte()])) + csharpAccessors(accessors, src)
+	} else {
+		header = headerText(node, src)
+	}
+	header = normalizeWhitespace(strings.TrimSuffix(header, ";"))
+
+	def := types.Definition{
+		Type:    defType,
+		Name:    name,
+		Line:    int(node.StartPoint().Row) + 1,
+		LineEnd: int(node.EndPoint().Row) + 1,
+		Id:      computeId(filePath, name, header),
+	}
+	def.Modifiers, def.Annotations = csharpModifiers(node, src)
+	parseCSharpDoc(extractCSharpComment(node, src), &def)
+
+	switch defType {
+	case "method", "constructor", "property", "event":
+		def.Signature = header
+	case "enum":
+		def.Definition = header + csharpEnumMembers(node, src)
+	default:
+		def.Definition = header
+	}
+	return &def
+}
+
+// extractCSharpEventFields builds a definition for each event declared by
+// an event field declaration such as "public event EventHandler A, B;"
+func extractCSharpEventFields(node *sitter.Node, src []byte, filePath string) []types.Definition {
+	var events []types.Definition
+	signature := normalizeWhitespace(strings.TrimSuffix(strings.TrimSpace(node.Content(src)), ";"))
+	modifiers, annotations := csharpModifiers(node, src)
+	comment := extractCSharpComment(node, src)
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		decl := node.NamedChild(i)
+		if decl.Type() != "variable_declaration" {
+			continue
+		}
+		for j := 0; j < int(decl.NamedChildCount()); j++ {
+			v := decl.NamedChild(j)
+			if v.Type() != "variable_declarator" {
+				continue
+			}
+			nameNode := v.ChildByFieldName("name")
+			if nameNode == nil {
+				continue
+			}
+			name := nameNode.Content(src)
+			def := types.Definition{
+				Type:        "event",
+				Name:        name,
+				Line:        int(node.StartPoint().Row) + 1,
+				LineEnd:     int(node.EndPoint().Row) + 1,
+				Id:          computeId(filePath, name, signature),
+				Signature:   signature,
+				Modifiers:   modifiers,
+				Annotations: annotations,
+			}
+			parseCSharpDoc(comment, &def)
+			events = append(events, def)
+		}
+	}
+	return events
+}
+
+// csharpAccessors renders an accessor list without bodies, e.g. " { get; private set; }"
+func csharpAccessors(node *sitter.Node, src []byte) string {
+	var accessors []string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		accessor := node.NamedChild(i)
+		if accessor.Type() != "accessor_declaration" {
+			continue
+		}
+		accessors = append(accessors, strings.TrimSuffix(headerText(accessor, src), ";")+";")
+	}
+	if len(accessors) == 0 {
+		return ""
+	}
+	return " { " + strings.Join(accessors, " ") + " }"
+}
+
+// csharpModifiers splits a declaration's modifiers into keywords and attributes
+func csharpModifiers(node *sitter.Node, src []byte) ([]string, []string) {
+	var modifiers, attributes []string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		switch child.Type() {
+		case "modifier":
+			modifiers = append(modifiers, child.Content(src))
+		case "attribute_list":
+			attributes = append(attributes, normalizeWhitespace(child.Content(src)))
+		}
+	}
+	return modifiers, attributes
+}
+
+// csharpEnumMembers renders the members of an enum body as " { A, B }"
+func csharpEnumMembers(node *sitter.Node, src []byte) string {
+	body := node.ChildByFieldName("body")
+	if body == nil {
+		return ""
+	}
+	var members []string
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		if child := body.NamedChild(i); child.Type() == "enum_member_declaration" {
+			if name := child.ChildByFieldName("name"); name != nil {
+				members = append(members, name.Content(src))
+			}
+		}
+	}
+	if len(members) == 0 {
+		return ""
+	}
+	return " { " + strings.Join(members, ", ") + " }"
+}
+
+// extractCSharpComment collects the /// (or /** */) doc comment lines
+// directly above node, without their markers
+func extractCSharpComment(node *sitter.Node, src []byte) string {
+	var lines []string
+	row := node.StartPoint().Row
+	for prev := node.PrevNamedSibling(); prev != nil && prev.Type() == "comment"; prev = prev.PrevNamedSibling() {
+		if prev.EndPoint().Row+1 < row {
+			break
+		}
+		text := prev.Content(src)
+		switch {
+		case strings.HasPrefix(text, "///"):
+			text = strings.TrimPrefix(strings.TrimPrefix(text, "///"), " ")
+		case strings.HasPrefix(text, "/**"):
+			text = cleanDocBlock(text)
+		default:
+			return strings.Join(lines, "\n")
+		}
+		lines = append([]string{strings.TrimRight(text, "\r ")}, lines...)
+		row = prev.StartPoint().Row
+	}
+	return strings.Join(lines, "\n")
+}
+
+// csharpXMLDoc is the subset of the C# XML documentation format that is
+// mapped onto a definition
+type csharpXMLDoc struct {
+	Summary    *csharpXMLText  `xml:"summary"`
+	Remarks    *csharpXMLText  `xml:"remarks"`
+	Params     []csharpXMLText `xml:"param"`
+	Returns    *csharpXMLText  `xml:"returns"`
+	Value      *csharpXMLText  `xml:"value"`
+	Exceptions []csharpXMLText `xml:"exception"`
+}
+
+type csharpXMLText struct {
+	Name  string `xml:"name,attr"`
+	Cref  string `xml:"cref,attr"`
+	Inner string `xml:",innerxml"`
+}
+
+var (
+	csharpDocRef = regexp.MustCompile(`<(?:see|seealso|paramref|typeparamref)\s+(?:cref|name|langword|href)="([^"]*)"\s*/>`)
+	csharpDocTag = regexp.MustCompile(`<[^>]*>`)
+)
+
+// parseCSharpDoc maps an XML doc comment onto def: <summary> and <remarks>
+// become the comment, and <param>, <returns> and <exception> become the
+// structured parameter, return and raise fields. Comments that are not
+// well-formed XML doc are kept verbatim.
+func parseCSharpDoc(doc string, def *types.Definition) {
+	if doc == "" {
+		return
+	}
+	var parsed csharpXMLDoc
+	if err := xml.Unmarshal([]byte("<doc>"+doc+"</doc>"), &parsed); err != nil || parsed.Summary == nil {
+		def.Comment = doc
+		return
+	}
+
+	def.Comment = csharpDocText(parsed.Summary.Inner)
+	if parsed.Remarks != nil {
+		def.Comment = strings.TrimSpace(def.Comment + "\n" + csharpDocText(parsed.Remarks.Inner))
+	}
+	for _, param := range parsed.Params {
+		def.Params = append(def.Params, types.DocParam{Name: param.Name, Description: csharpDocText(param.Inner)})
+	}
+	if returns := parsed.Returns; returns != nil {
+		def.Returns = &types.DocParam{Description: csharpDocText(returns.Inner)}
+	} else if value := parsed.Value; value != nil {
+		def.Returns = &types.DocParam{Description: csharpDocText(value.Inner)}
+	}
+	for _, exception := range parsed.Exceptions {
+		def.Raises = append(def.Raises, types.DocParam{Type: csharpCref(exception.Cref), Description: csharpDocText(exception.Inner)})
+	}
+}
+
+// csharpDocText flattens the inner XML of a doc element to plain text,
+// replacing references such as <see cref="T"/> by their target
+func csharpDocText(inner string) string {
+	text := csharpDocRef.ReplaceAllStringFunc(inner, func(ref string) string {
+		return csharpCref(csharpDocRef.FindStringSubmatch(ref)[1])
+	})
+	text = csharpDocTag.ReplaceAllString(text, "")
+	return strings.TrimSpace(normalizeWhitespace(html.UnescapeString(text)))
+}
+
+// csharpCref strips the member kind prefix of a cref, e.g. "T:System.Exception"
+func csharpCref(cref string) string {
+	if len(cref) > 2 && cref[1] == ':' {
+		return cref[2:]
+	}
+	return cref
+}
+
+// MergePartialTypes reduces the parts of each C# partial type to a single
+// definition, kept with the first part. It joins the doc comments and
+// attributes of every part, lists the files the parts are declared in, and
+// links the members of every part to the kept definition.
+func MergePartialTypes(files []types.FileMap) {
+	type ref struct{ file, def int }
+	parts := make(map[string][]ref)
+	var order []string
+	for fi, f := range files {
+		if f.Language != "csharp" {
+			continue
+		}
+		for di, d := range f.Definitions {
+			if !isPartial(d) {
+				continue
+			}
+			key := d.Type + " " + qualifiedNameOf(d)
+			if _, ok := parts[key]; !ok {
+				order = append(order, key)
+			}
+			parts[key] = append(parts[key], ref{fi, di})
+		}
+	}
+
+	merged := make(map[string]string)
+	removed := make(map[ref]bool)
+	for _, key := range order {
+		refs := parts[key]
+		if len(refs) < 2 {
+			continue
+		}
+		def := &files[refs[0].file].Definitions[refs[0].def]
+		docs := []string{}
+		if def.Comment != "" {
+			docs = append(docs, def.Comment)
+		}
+		var paths []string
+		for _, r := range refs {
+			appendUnique(&paths, files[r.file].Path)
+			if r == refs[0] {
+				continue
+			}
+			d := files[r.file].Definitions[r.def]
+			if d.Comment != "" {
+				appendUnique(&docs, d.Comment)
+			}
+			appendUnique(&def.Annotations, d.Annotations...)
+			merged[d.Id] = def.Id
+			removed[r] = true
+		}
+		def.Comment = strings.Join(docs, "\n\n")
+		if len(paths) > 1 {
+			def.Files = paths
+		}
+	}
+
+	for fi := range files {
+		kept := files[fi].Definitions[:0]
+		for di, d := range files[fi].Definitions {
+			if removed[ref{fi, di}] {
+				continue
+			}
+			if id, ok := merged[d.ParentId]; ok {
+				d.ParentId = id
+			}
+			kept = append(kept, d)
+		}
+		files[fi].Definitions = kept
+	}
+}
+
+// isPartial reports whether def is one part of a partial type
+func isPartial(def types.Definition) bool {
+	for _, modifier := range def.Modifiers {
+		if modifier == "partial" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"codemap/internal/types"
+)
+
+func TestCSharpParser_Parse(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "Player.cs")
+	testContent := `namespace Game.Core
+{
+    /// <summary>
+    /// A player entity.
+    /// </summary>
+    [Serializable]
+    public partial class Player : Entity
+    {
+        public string Name { get; private set; }
+
+        public event EventHandler Died;
+
+        /// <summary>Moves the <see cref="T:Game.Core.Player"/>.</summary>
+        /// <param name="dx">Delta x.</param>
+        /// <returns>True if moved.</returns>
+        /// <exception cref="ArgumentException">Bad delta.</exception>
+        public bool Move(int dx) => true;
+
+        public Player(int hp) {}
+    }
+
+    public interface IDamageable { void Damage(int amount); }
+    internal struct Vec { public float X; }
+    public record Point(int X, int Y);
+    public enum Color { Red, Green = 2 }
+}
+`
+	err := os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	parser := &CSharpParser{}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []struct {
+		name      string
+		typ       string
+		qualified string
+	}{
+		{"Game.Core", "namespace", ""},
+		{"Player", "class", "Game.Core.Player"},
+		{"Name", "property", "Game.Core.Player.Name"},
+		{"Died", "event", "Game.Core.Player.Died"},
+		{"Move", "method", "Game.Core.Player.Move"},
+		{"Player", "constructor", "Game.Core.Player.Player"},
+		{"IDamageable", "interface", "Game.Core.IDamageable"},
+		{"Damage", "method", "Game.Core.IDamageable.Damage"},
+		{"Vec", "struct", "Game.Core.Vec"},
+		{"Point", "record", "Game.Core.Point"},
+		{"Color", "enum", "Game.Core.Color"},
+	}
+
+	if len(definitions) != len(expected) {
+		t.Fatalf("Expected %d definitions, got %d", len(expected), len(definitions))
+	}
+
+	for i, exp := range expected {
+		if definitions[i].Name != exp.name {
+			t.Errorf("Definition %d: expected name %s, got %s", i, exp.name, definitions[i].Name)
+		}
+		if definitions[i].Type != exp.typ {
+			t.Errorf("Definition %d: expected type %s, got %s", i, exp.typ, definitions[i].Type)
+		}
+		if definitions[i].QualifiedName != exp.qualified {
+			t.Errorf("Definition %d: expected qualified name %q, got %q", i, exp.qualified, definitions[i].QualifiedName)
+		}
+	}
+
+	class := definitions[1]
+	if class.Comment != "A player entity." {
+		t.Errorf("Expected summary as comment, got %q", class.Comment)
+	}
+	if len(class.Annotations) != 1 || class.Annotations[0] != "[Serializable]" {
+		t.Errorf("Expected [Serializable] attribute, got %v", class.Annotations)
+	}
+	if want := "public string Name { get; private set; }"; definitions[2].Signature != want {
+		t.Errorf("Expected property signature %q, got %q", want, definitions[2].Signature)
+	}
+
+	method := definitions[4]
+	if method.Comment != "Moves the Game.Core.Player." {
+		t.Errorf("Expected summary with resolved cref, got %q", method.Comment)
+	}
+	if len(method.Params) != 1 || method.Params[0].Name != "dx" || method.Params[0].Description != "Delta x." {
+		t.Errorf("Unexpected params %+v", method.Params)
+	}
+	if method.Returns == nil || method.Returns.Description != "True if moved." {
+		t.Errorf("Unexpected returns %+v", method.Returns)
+	}
+	if len(method.Raises) != 1 || method.Raises[0].Type != "ArgumentException" {
+		t.Errorf("Unexpected raises %+v", method.Raises)
+	}
+	if want := "public enum Color { Red, Green }"; definitions[10].Definition != want {
+		t.Errorf("Expected enum definition %q, got %q", want, definitions[10].Definition)
+	}
+}
+
+func TestMergePartialTypes(t *testing.T) {
+	tempDir := t.TempDir()
+
+	first := filepath.Join(tempDir, "Player.cs")
+	second := filepath.Join(tempDir, "Player.Health.cs")
+	os.WriteFile(first, []byte("namespace Game;\n/// <summary>A player.</summary>\npartial class Player { void Move() {} }\n"), 0644)
+	os.WriteFile(second, []byte("namespace Game;\n/// <summary>Health tracking.</summary>\npartial class Player { int Health { get; } }\n"), 0644)
+
+	parser := &CSharpParser{}
+	var files []types.FileMap
+	for _, path := range []string{first, second} {
+		defs, err := parser.Parse(path)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		files = append(files, types.FileMap{Path: path, Language: "csharp", Definitions: defs})
+	}
+
+	MergePartialTypes(files)
+
+	a, b := files[0].Definitions, files[1].Definitions
+	if len(a) != 3 || len(b) != 2 {
+		t.Fatalf("Expected one Player definition in the first file, got %+v and %+v", a, b)
+	}
+	if b[1].Name != "Health" || b[1].ParentId != a[1].Id {
+		t.Errorf("Expected Health to be linked to the merged class")
+	}
+	if a[1].Comment != "A player.\n\nHealth tracking." {
+		t.Errorf("Expected the doc comments to be merged, got %q", a[1].Comment)
+	}
+	if len(a[1].Files) != 2 || a[1].Files[1] != second {
+		t.Errorf("Expected the files of both parts, got %v", a[1].Files)
+	}
+}
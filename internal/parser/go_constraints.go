@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"go/ast"
+	"go/build/constraint"
+	"path/filepath"
+	"strings"
+)
+
+// Operating systems and architectures recognized in file name suffixes,
+// as listed by go/build
+var (
+	knownGOOS = map[string]bool{
+		"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+		"hurd": true, "illumos": true, "ios": true, "js": true, "linux": true, "nacl": true,
+		"netbsd": true, "openbsd": true, "plan9": true, "solaris": true, "wasip1": true,
+		"windows": true, "zos": true,
+	}
+	knownGOARCH = map[string]bool{
+		"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true,
+		"arm64": true, "arm64be": true, "loong64": true, "mips": true, "mipsle": true,
+		"mips64": true, "mips64le": true, "mips64p32": true, "mips64p32le": true,
+		"ppc": true, "ppc64": true, "ppc64le": true, "riscv": true, "riscv64": true,
+		"s390": true, "s390x": true, "sparc": true, "sparc64": true, "wasm": true,
+	}
+	// Operating systems that also satisfy the build tag of another
+	impliedGOOS = map[string]string{"android": "linux", "ios": "darwin", "illumos": "solaris"}
+)
+
+// goBuildConstraint returns the build constraint a Go file is compiled
+// under, as a normalized expression such as "linux && (amd64 || arm64)".
+// It combines the //go:build line, or the legacy // +build lines when there
+// is none, with the GOOS and GOARCH implied by the file name and the cgo
+// requirement of files importing "C". It is "" for unconstrained files.
+func goBuildConstraint(filePath string, file *ast.File) string {
+	var goBuild, plusBuild constraint.Expr
+	for _, cg := range file.Comments {
+		if cg.Pos() >= file.Package {
+			break
+		}
+		for _, c := range cg.List {
+			if constraint.IsGoBuild(c.Text) {
+				if x, err := constraint.Parse(c.Text); err == nil {
+					goBuild = x
+				}
+			} else if constraint.IsPlusBuild(c.Text) {
+				if x, err := constraint.Parse(c.Text); err == nil {
+					plusBuild = andConstraint(plusBuild, x)
+				}
+			}
+		}
+	}
+	expr := goBuild
+	if expr == nil {
+		expr = plusBuild
+	}
+
+	for _, tag := range fileNameConstraints(filePath) {
+		expr = andConstraint(expr, &constraint.TagExpr{Tag: tag})
+	}
+	for _, imp := range file.Imports {
+		if imp.Path.Value == `"C"` {
+			expr = andConstraint(expr, &constraint.TagExpr{Tag: "cgo"})
+			break
+		}
+	}
+
+	if expr == nil {
+		return ""
+	}
+	return expr.String()
+}
+
+// fileNameConstraints returns the GOOS and GOARCH a file is restricted to by
+// a *_GOOS, *_GOARCH or *_GOOS_GOARCH name, ignoring a _test suffix. As in
+// go/build, the name ends at its first dot, and a GOOS brings the one it
+// implies, as android does linux.
+func fileNameConstraints(filePath string) []string {
+	name, _, _ := strings.Cut(filepath.Base(filePath), ".")
+	name = strings.TrimSuffix(name, "_test")
+	parts := strings.Split(name, "_")
+	n := len(parts)
+	var tags []string
+	switch {
+	case n >= 3 && knownGOOS[parts[n-2]] && knownGOARCH[parts[n-1]]:
+		tags = []string{parts[n-2], parts[n-1]}
+	case n >= 2 && (knownGOOS[parts[n-1]] || knownGOARCH[parts[n-1]]):
+		tags = []string{parts[n-1]}
+	}
+	if len(tags) > 0 {
+		if implied, ok := impliedGOOS[tags[0]]; ok {
+			tags = append([]string{tags[0], implied}, tags[1:]...)
+		}
+	}
+	return tags
+}
+
+// andConstraint joins two constraints with &&, either of which may be nil
+func andConstraint(x, y constraint.Expr) constraint.Expr {
+	if x == nil {
+		return y
+	}
+	return &constraint.AndExpr{X: x, Y: y}
+}
+
+// goDirectives returns the compiler and cgo directives in a doc comment,
+// such as //go:noinline or //export Name, which ast.CommentGroup.Text drops
+func goDirectives(cg *ast.CommentGroup) []string {
+	if cg == nil {
+		return nil
+	}
+	var directives []string
+	for _, c := range cg.List {
+		text := c.Text
+		if strings.HasPrefix(text, "//go:") || strings.HasPrefix(text, "//export ") || strings.HasPrefix(text, "//extern ") {
+			directives = append(directives, strings.TrimSpace(text))
+		}
+	}
+	return directives
+}
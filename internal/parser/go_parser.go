@@ -1,10 +1,13 @@
 package parser
 
 import (
+	"context"
 	"go/ast"
 	"go/parser"
+	"go/scanner"
 	"go/token"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -14,25 +17,57 @@ import (
 // GoParser implements the Parser interface for Go source files
 type GoParser struct{}
 
-// Parse extracts definitions from a Go source file
+// Parse reads filePath and extracts its definitions with ParseContent
 func (p *GoParser) Parse(filePath string) ([]types.Definition, error) {
-	fset := token.NewFileSet()
-	src, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
-	if err != nil {
+	return parseFile(p, filePath)
+}
+
+// ParseContent extracts definitions from Go source. go/parser cannot be
+// interrupted, so ctx is only checked before parsing starts.
+func (p *GoParser) ParseContent(ctx context.Context, filePath string, src []byte) ([]types.Definition, error) {
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
-
-	srcBytes, err := os.ReadFile(filePath)
-	if err != nil {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
+	// A file with syntax errors still yields the declarations parsed
+	// around them
+	var diagnostics []types.Diagnostic
+	if list, ok := err.(scanner.ErrorList); ok && file != nil {
+		for _, e := range list {
+			diagnostics = append(diagnostics, types.Diagnostic{Severity: "error", Line: e.Pos.Line, Message: e.Msg})
+		}
+	} else if err != nil {
 		return nil, err
 	}
+	if file.Name == nil || file.Name.Name == "" {
+		// Nothing is parsed past a broken package clause
+		return nil, partialResult(diagnostics)
+	}
 
-	var definitions []types.Definition
+	// The package clause is mapped per file; MergeGoPackages keeps one
+	// definition per package once all files are parsed
+	pkgDecl := "package " + file.Name.Name
+	definitions := []types.Definition{{
+		Type:       "package",
+		Name:       file.Name.Name,
+		Line:       fset.Position(file.Package).Line,
+		LineEnd:    fset.Position(file.Name.End()).Line,
+		Id:         computeId(filePath, file.Name.Name, pkgDecl),
+		Definition: pkgDecl,
+		Comment:    extractComment(file.Doc),
+	}}
+
+	// Only package-level consts and vars are mapped, not function locals
+	topLevel := make(map[ast.Decl]bool)
+	for _, decl := range file.Decls {
+		topLevel[decl] = true
+	}
 
-	ast.Inspect(src, func(n ast.Node) bool {
+	ast.Inspect(file, func(n ast.Node) bool {
 		switch node := n.(type) {
 		case *ast.FuncDecl:
-			sig := extractSignature(srcBytes, fset, node)
+			sig := extractSignature(src, fset, node)
 			def := types.Definition{
 				Type:      "function",
 				Name:      node.Name.Name,
@@ -41,45 +76,430 @@ func (p *GoParser) Parse(filePath string) ([]types.Definition, error) {
 				Id:        computeId(filePath, node.Name.Name, sig),
 				Signature: sig,
 				Comment:   extractComment(node.Doc),
+				Annotations: goDirectives(node.Doc),
+				TypeParams: goTypeParams(src, fset, node.Type.TypeParams),
+			}
+			if recv := receiverTypeName(node); recv != "" {
+				def.Type = "method"
+				def.Parent = recv
+				def.QualifiedName = recv + "." + node.Name.Name
+				def.Id = computeId(filePath, def.QualifiedName, sig)
 			}
 			definitions = append(definitions, def)
 		case *ast.GenDecl:
+			if node.Tok == token.CONST || node.Tok == token.VAR {
+				if topLevel[node] {
+					definitions = append(definitions, extractValueDefinitions(src, fset, filePath, node)...)
+				}
+				return true
+			}
 			for _, spec := range node.Specs {
 				if typeSpec, ok := spec.(*ast.TypeSpec); ok {
-					defn := extractTypeDefinition(srcBytes, fset, node, typeSpec)
+					defn := extractTypeDefinition(src, fset, node, typeSpec)
+					// Grouped specs have their own doc; the group's doc
+					// only belongs to a lone declaration
+					comment := extractComment(typeSpec.Doc)
+					if comment == "" && !node.Lparen.IsValid() {
+						comment = extractComment(node.Doc)
+					}
 					def := types.Definition{
 						Type:       "type",
 						Name:       typeSpec.Name.Name,
 						Line:        fset.Position(typeSpec.Pos()).Line,
-						LineEnd:     fset.Position(node.End()).Line,
+						LineEnd:     fset.Position(typeSpec.End()).Line,
 						Id:         computeId(filePath, typeSpec.Name.Name, defn),
 						Definition: defn,
-						Comment:    extractComment(node.Doc),
+						Comment:    comment,
+						TypeParams: goTypeParams(src, fset, typeSpec.TypeParams),
 					}
 					definitions = append(definitions, def)
+					definitions = append(definitions, extractMemberDefinitions(src, fset, filePath, typeSpec, &def)...)
 				}
 			}
 		}
 		return true
 	})
 
-	return definitions, nil
+	// Link methods to their receiver type and enum constants to their type
+	// when it is declared in this file
+	typeIds := make(map[string]string)
+	for _, def := range definitions {
+		if def.Type == "type" {
+			typeIds[def.Name] = def.Id
+		}
+	}
+	for i := range definitions {
+		if definitions[i].Type == "method" || (definitions[i].Type == "const" && definitions[i].Parent != "") {
+			definitions[i].ParentId = typeIds[definitions[i].Parent]
+		}
+	}
+
+	// Every definition is compiled under the file's build constraint, but
+	// the package spans files that may have different ones
+	if build := goBuildConstraint(filePath, file); build != "" {
+		for i := range definitions {
+			if definitions[i].Type != "package" {
+				definitions[i].Build = build
+			}
+		}
+	}
+
+	return definitions, partialResult(diagnostics)
 }
 
-// extractSignature extracts the function signature from source bytes
+// MergeGoPackages reduces the package definitions of Go files to one per
+// package, kept in doc.go when there is one and otherwise in the first file
+// with a package doc. It merges the doc comments of every file, lists the
+// package's files and is qualified by the import path derived from go.mod,
+// which is also recorded as the Package of each file.
+func MergeGoPackages(files []types.FileMap) {
+	type ref struct{ file, def int }
+	packages := make(map[string][]ref)
+	var order []string
+	for fi, f := range files {
+		if f.Language != "go" {
+			continue
+		}
+		for di, d := range f.Definitions {
+			if d.Type != "package" {
+				continue
+			}
+			key := filepath.Dir(f.Path) + " " + d.Name
+			if _, ok := packages[key]; !ok {
+				order = append(order, key)
+			}
+			packages[key] = append(packages[key], ref{fi, di})
+			break
+		}
+	}
+
+	removed := make(map[ref]bool)
+	for _, key := range order {
+		refs := packages[key]
+		primary := refs[0]
+		for _, r := range refs {
+			if filepath.Base(files[r.file].Path) == "doc.go" {
+				primary = r
+				break
+			}
+		}
+		if files[primary.file].Definitions[primary.def].Comment == "" {
+			for _, r := range refs {
+				if files[r.file].Definitions[r.def].Comment != "" {
+					primary = r
+					break
+				}
+			}
+		}
+
+		def := &files[primary.file].Definitions[primary.def]
+		importPath := goImportPath(files[primary.file].Path, def.Name)
+		docs := []string{}
+		if def.Comment != "" {
+			docs = append(docs, def.Comment)
+		}
+		var paths []string
+		for _, r := range refs {
+			files[r.file].Package = importPath
+			paths = append(paths, files[r.file].Path)
+			if r == primary {
+				continue
+			}
+			if c := files[r.file].Definitions[r.def].Comment; c != "" && !containsString(docs, c) {
+				docs = append(docs, c)
+			}
+			removed[r] = true
+		}
+
+		def.Comment = strings.Join(docs, "\n\n")
+		def.QualifiedName = importPath
+		def.Files = paths
+		def.Id = computeId(filepath.Dir(files[primary.file].Path), importPath, def.Definition)
+	}
+
+	for fi := range files {
+		kept := files[fi].Definitions[:0]
+		for di, d := range files[fi].Definitions {
+			if !removed[ref{fi, di}] {
+				kept = append(kept, d)
+			}
+		}
+		files[fi].Definitions = kept
+	}
+}
+
+var goModule = regexp.MustCompile(`(?m)^\s*module\s+"?([^"\s]+)"?`)
+
+// goImportPath returns the import path of the package named name in the
+// directory of filePath, from the module path of the nearest go.mod. External
+// test packages get a _test suffix, and without a go.mod the package name is
+// used.
+func goImportPath(filePath, name string) string {
+	dir, err := filepath.Abs(filepath.Dir(filePath))
+	if err != nil {
+		return name
+	}
+	suffix := ""
+	if strings.HasSuffix(name, "_test") {
+		suffix = "_test"
+	}
+	for d := dir; ; d = filepath.Dir(d) {
+		if data, err := os.ReadFile(filepath.Join(d, "go.mod")); err == nil {
+			m := goModule.FindSubmatch(data)
+			if m == nil {
+				return name
+			}
+			rel, err := filepath.Rel(d, dir)
+			if err != nil || rel == "." {
+				return string(m[1]) + suffix
+			}
+			return string(m[1]) + "/" + filepath.ToSlash(rel) + suffix
+		}
+		if filepath.Dir(d) == d {
+			return name
+		}
+	}
+}
+
+// extractSignature extracts the function signature from source bytes. A
+// function without a body, such as one implemented in assembly or cut short
+// by a syntax error, ends with its declaration.
 func extractSignature(src []byte, fset *token.FileSet, node *ast.FuncDecl) string {
-	start := fset.Position(node.Pos()).Offset
-	end := fset.Position(node.Body.Pos()).Offset
-	sig := string(src[start:end])
-	return normalizeWhitespace(strings.TrimSpace(sig))
+	end := node.End()
+	if node.Body != nil {
+		end = node.Body.Pos()
+	}
+	return sourceText(src, fset, node.Pos(), end)
 }
 
-// extractTypeDefinition extracts the type definition from source bytes
+// goTypeParams returns the type parameters of a generic function or type
+// with their constraints, one entry per name
+func goTypeParams(src []byte, fset *token.FileSet, fields *ast.FieldList) []types.TypeParam {
+	if fields == nil {
+		return nil
+	}
+	var params []types.TypeParam
+	for _, field := range fields.List {
+		constraint := nodeText(src, fset, field.Type)
+		for _, ident := range field.Names {
+			params = append(params, types.TypeParam{Name: ident.Name, Constraint: constraint})
+		}
+	}
+	return params
+}
+
+// receiverTypeName returns the base type name of a method's receiver,
+// without pointer or type parameters, or "" for plain functions
+func receiverTypeName(node *ast.FuncDecl) string {
+	if node.Recv == nil || len(node.Recv.List) == 0 {
+		return ""
+	}
+	expr := node.Recv.List[0].Type
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.ParenExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// extractTypeDefinition extracts the type definition from source bytes. A
+// lone declaration keeps its keyword; grouped specs are shown alone.
 func extractTypeDefinition(src []byte, fset *token.FileSet, genDecl *ast.GenDecl, typeSpec *ast.TypeSpec) string {
-	start := fset.Position(genDecl.Pos()).Offset
-	end := fset.Position(genDecl.End()).Offset
-	def := string(src[start:end])
-	return normalizeWhitespace(strings.TrimSpace(def))
+	if genDecl.Lparen.IsValid() {
+		return nodeText(src, fset, typeSpec)
+	}
+	return sourceText(src, fset, genDecl.Pos(), genDecl.End())
+}
+
+// extractValueDefinitions emits one definition per name in a const or var
+// declaration. The constants of a parenthesized block that uses iota are
+// members of their declared type, which later specs repeat implicitly.
+func extractValueDefinitions(src []byte, fset *token.FileSet, filePath string, genDecl *ast.GenDecl) []types.Definition {
+	var definitions []types.Definition
+	kind := genDecl.Tok.String()
+	enum := genDecl.Tok == token.CONST && genDecl.Lparen.IsValid() && usesIota(genDecl)
+
+	var enumType string
+	for _, spec := range genDecl.Specs {
+		valueSpec, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		// A lone declaration keeps its keyword; grouped specs are shown alone
+		var defn string
+		if genDecl.Lparen.IsValid() {
+			defn = nodeText(src, fset, valueSpec)
+		} else {
+			defn = nodeText(src, fset, genDecl)
+		}
+		comment := extractComment(valueSpec.Doc)
+		if comment == "" {
+			comment = extractComment(valueSpec.Comment)
+		}
+		if comment == "" && len(genDecl.Specs) == 1 {
+			comment = extractComment(genDecl.Doc)
+		}
+		if enum {
+			if ident, ok := valueSpec.Type.(*ast.Ident); ok {
+				enumType = ident.Name
+			} else if valueSpec.Type != nil || len(valueSpec.Values) > 0 {
+				enumType = ""
+			}
+		}
+		for _, ident := range valueSpec.Names {
+			if ident.Name == "_" {
+				continue
+			}
+			def := types.Definition{
+				Type:       kind,
+				Name:       ident.Name,
+				Line:       fset.Position(valueSpec.Pos()).Line,
+				LineEnd:    fset.Position(valueSpec.End()).Line,
+				Definition: defn,
+				Comment:    comment,
+			}
+			if enumType != "" {
+				def.Parent = enumType
+				def.QualifiedName = enumType + "." + ident.Name
+			}
+			def.Id = computeId(filePath, ident.Name, defn)
+			definitions = append(definitions, def)
+		}
+	}
+	return definitions
+}
+
+// usesIota reports whether any value in a const declaration refers to iota
+func usesIota(genDecl *ast.GenDecl) bool {
+	found := false
+	ast.Inspect(genDecl, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && ident.Name == "iota" {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// extractMemberDefinitions emits the fields of a struct type and the
+// methods and embedded types of an interface as children of parent
+func extractMemberDefinitions(src []byte, fset *token.FileSet, filePath string, typeSpec *ast.TypeSpec, parent *types.Definition) []types.Definition {
+	var fields *ast.FieldList
+	isInterface := false
+	switch t := typeSpec.Type.(type) {
+	case *ast.StructType:
+		fields = t.Fields
+	case *ast.InterfaceType:
+		fields = t.Methods
+		isInterface = true
+	}
+	if fields == nil {
+		return nil
+	}
+
+	var definitions []types.Definition
+	for _, field := range fields.List {
+		comment := extractComment(field.Doc)
+		if comment == "" {
+			comment = extractComment(field.Comment)
+		}
+		var tag string
+		if field.Tag != nil {
+			tag = field.Tag.Value
+		}
+
+		var names []string
+		defType := "field"
+		for _, ident := range field.Names {
+			names = append(names, ident.Name)
+		}
+		if len(names) == 0 {
+			// Embedded types are named after the type without package or pointer
+			name := embeddedTypeName(field.Type)
+			if name == "" {
+				continue
+			}
+			names = append(names, name)
+			defType = "embedded"
+		} else if _, ok := field.Type.(*ast.FuncType); ok && isInterface {
+			defType = "method"
+		}
+
+		defn := nodeText(src, fset, field)
+		for _, name := range names {
+			def := types.Definition{
+				Type:    defType,
+				Name:    name,
+				Line:    fset.Position(field.Pos()).Line,
+				LineEnd: fset.Position(field.End()).Line,
+				Comment: comment,
+				Tag:     tag,
+			}
+			if defType == "method" {
+				def.Signature = defn
+			} else {
+				def.Definition = defn
+			}
+			linkParent(&def, parent)
+			def.Id = computeId(filePath, def.QualifiedName, defn)
+			definitions = append(definitions, def)
+		}
+	}
+	return definitions
+}
+
+// embeddedTypeName returns the name an embedded type is referred to by
+func embeddedTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return embeddedTypeName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.IndexExpr:
+		return embeddedTypeName(t.X)
+	case *ast.IndexListExpr:
+		return embeddedTypeName(t.X)
+	}
+	return ""
+}
+
+// nodeText returns the whitespace-normalized source of a node
+func nodeText(src []byte, fset *token.FileSet, node ast.Node) string {
+	return sourceText(src, fset, node.Pos(), node.End())
+}
+
+// sourceText returns the whitespace-normalized source between two
+// positions. Nodes cut short by a syntax error can end at an invalid
+// position, so the text then runs to the end of the line it starts on.
+func sourceText(src []byte, fset *token.FileSet, from, to token.Pos) string {
+	start := fset.Position(from).Offset
+	if !from.IsValid() || start > len(src) {
+		return ""
+	}
+	end := -1
+	if to.IsValid() {
+		end = fset.Position(to).Offset
+	}
+	if end < start || end > len(src) {
+		end = len(src)
+		if i := strings.IndexByte(string(src[start:]), '\n'); i >= 0 {
+			end = start + i
+		}
+	}
+	return normalizeWhitespace(strings.TrimSpace(string(src[start:end])))
 }
 
 // extractComment extracts the comment text from a comment group
@@ -0,0 +1,354 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"codemap/internal/types"
+)
+
+func TestGoParser_Parse(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "test.go")
+	testContent := `package test
+
+// Color is a color
+type Color int
+
+const (
+	// Red is red
+	Red Color = iota
+	Green
+)
+
+const (
+	_  = iota
+	KB = 1 << (10 * iota)
+)
+
+// MaxSize bounds the buffer
+const MaxSize = 10
+
+var ErrEmpty = errors.New("empty")
+
+// Config holds settings
+type Config struct {
+	// Name names the config
+	Name string ` + "`yaml:\"name\"`" + `
+}
+
+// Shape has an area
+type Shape interface {
+	Area() float64
+}
+
+// Load reads a config
+func (c *Config) Load() error {
+	const local = 1
+	return nil
+}
+`
+	err := os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	parser := &GoParser{}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []struct {
+		name   string
+		typ    string
+		parent string
+	}{
+		{"test", "package", ""},
+		{"Color", "type", ""},
+		{"Red", "const", "Color"},
+		{"Green", "const", "Color"},
+		{"KB", "const", ""},
+		{"MaxSize", "const", ""},
+		{"ErrEmpty", "var", ""},
+		{"Config", "type", ""},
+		{"Name", "field", "Config"},
+		{"Shape", "type", ""},
+		{"Area", "method", "Shape"},
+		{"Load", "method", "Config"},
+	}
+
+	if len(definitions) != len(expected) {
+		t.Fatalf("Expected %d definitions, got %d", len(expected), len(definitions))
+	}
+
+	for i, exp := range expected {
+		if definitions[i].Name != exp.name {
+			t.Errorf("Definition %d: expected name %s, got %s", i, exp.name, definitions[i].Name)
+		}
+		if definitions[i].Type != exp.typ {
+			t.Errorf("Definition %d: expected type %s, got %s", i, exp.typ, definitions[i].Type)
+		}
+		if definitions[i].Parent != exp.parent {
+			t.Errorf("Definition %d: expected parent %q, got %q", i, exp.parent, definitions[i].Parent)
+		}
+	}
+
+	if definitions[2].ParentId != definitions[1].Id || definitions[3].ParentId != definitions[1].Id {
+		t.Errorf("Expected Red and Green to be linked to Color")
+	}
+	if q := definitions[3].QualifiedName; q != "Color.Green" {
+		t.Errorf("Expected qualified name Color.Green, got %q", q)
+	}
+	if kb := definitions[4]; kb.ParentId != "" || kb.QualifiedName != "" {
+		t.Errorf("Expected untyped KB to have no parent, got %q", kb.QualifiedName)
+	}
+	if name := definitions[8]; name.Tag != "`yaml:\"name\"`" || name.Comment != "Name names the config" {
+		t.Errorf("Unexpected field tag %q or comment %q", name.Tag, name.Comment)
+	}
+	if definitions[11].ParentId != definitions[7].Id {
+		t.Errorf("Expected Load to be linked to Config")
+	}
+}
+
+func TestGoParser_Generics(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "generic.go")
+	testContent := `package test
+
+// Map applies f to each element
+func Map[T, U any](s []T, f func(T) U) []U {
+	return nil
+}
+
+// Set holds comparable values
+type Set[K comparable, V ~int | ~string] map[K]V
+`
+	err := os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	parser := &GoParser{}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(definitions) != 3 {
+		t.Fatalf("Expected 3 definitions, got %d", len(definitions))
+	}
+	definitions = definitions[1:]
+
+	expected := [][]types.TypeParam{
+		{{Name: "T", Constraint: "any"}, {Name: "U", Constraint: "any"}},
+		{{Name: "K", Constraint: "comparable"}, {Name: "V", Constraint: "~int | ~string"}},
+	}
+	for i, exp := range expected {
+		got := definitions[i].TypeParams
+		if len(got) != len(exp) {
+			t.Errorf("Definition %d: expected %d type params, got %+v", i, len(exp), got)
+			continue
+		}
+		for j := range exp {
+			if got[j] != exp[j] {
+				t.Errorf("Definition %d: expected type param %+v, got %+v", i, exp[j], got[j])
+			}
+		}
+	}
+	if definitions[0].Build != "" {
+		t.Errorf("Expected no build constraint, got %q", definitions[0].Build)
+	}
+}
+
+func TestGoParser_GroupedTypes(t *testing.T) {
+	src := []byte(`package shapes
+
+// Shapes and their areas
+type (
+	// Point is a position
+	Point struct {
+		X, Y int
+	}
+
+	// Shape has an area
+	Shape interface {
+		Area() float64
+	}
+)
+`)
+
+	definitions, err := (&GoParser{}).ParseContent(context.Background(), "shapes.go", src)
+	if err != nil {
+		t.Fatalf("ParseContent failed: %v", err)
+	}
+	if len(definitions) != 6 {
+		t.Fatalf("Expected 6 definitions, got %d", len(definitions))
+	}
+
+	point, shape := definitions[1], definitions[4]
+	if point.Name != "Point" || point.Comment != "Point is a position" || point.Line != 6 || point.LineEnd != 8 {
+		t.Errorf("Unexpected Point %+v", point)
+	}
+	if point.Definition != "Point struct { X, Y int }" {
+		t.Errorf("Expected Point's own text, got %q", point.Definition)
+	}
+	if shape.Name != "Shape" || shape.Comment != "Shape has an area" || shape.Line != 11 || shape.LineEnd != 13 {
+		t.Errorf("Unexpected Shape %+v", shape)
+	}
+	if definitions[5].Name != "Area" || definitions[5].ParentId != shape.Id {
+		t.Errorf("Expected Area to be linked to Shape, got %+v", definitions[5])
+	}
+}
+
+func TestGoParser_BuildConstraints(t *testing.T) {
+	tempDir := t.TempDir()
+
+	tests := []struct {
+		file    string
+		content string
+		build   string
+	}{
+		{"net.go", "//go:build linux && (amd64 || arm64)\n\npackage test\n\nfunc Open() {}\n", "linux && (amd64 || arm64)"},
+		{"legacy.go", "// +build darwin,!ios\n\npackage test\n\nfunc Open() {}\n", "darwin && !ios"},
+		{"file_windows.go", "package test\n\nfunc Open() {}\n", "windows"},
+		{"file_linux_arm64_test.go", "//go:build integration\n\npackage test\n\nfunc Open() {}\n", "integration && linux && arm64"},
+		{"api_linux.pb.go", "package test\n\nfunc Open() {}\n", "linux"},
+		{"view_ios_arm64.go", "package test\n\nfunc Open() {}\n", "ios && darwin && arm64"},
+		{"app_android.go", "package test\n\nfunc Open() {}\n", "android && linux"},
+		{"native.go", "package test\n\n// #include <stdlib.h>\nimport \"C\"\n\n//export Open\nfunc Open() {}\n", "cgo"},
+	}
+
+	for _, tt := range tests {
+		testFile := filepath.Join(tempDir, tt.file)
+		if err := os.WriteFile(testFile, []byte(tt.content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		parser := &GoParser{}
+		definitions, err := parser.Parse(testFile)
+		if err != nil {
+			t.Fatalf("Parse of %s failed: %v", tt.file, err)
+		}
+		if len(definitions) != 2 {
+			t.Fatalf("%s: expected 2 definitions, got %d", tt.file, len(definitions))
+		}
+		if definitions[1].Build != tt.build {
+			t.Errorf("%s: expected build %q, got %q", tt.file, tt.build, definitions[1].Build)
+		}
+	}
+
+	definitions, _ := (&GoParser{}).Parse(filepath.Join(tempDir, "native.go"))
+	if annotations := definitions[1].Annotations; len(annotations) != 1 || annotations[0] != "//export Open" {
+		t.Errorf("Expected the cgo export directive, got %v", annotations)
+	}
+}
+
+func TestGoParser_ParseContent(t *testing.T) {
+	// The path is only used to name the file, so it need not exist
+	filePath := filepath.Join(t.TempDir(), "buffer_windows.go")
+	src := []byte("package buffer\n\n// Flush writes pending edits\nfunc Flush() error { return nil }\n")
+
+	definitions, err := (&GoParser{}).ParseContent(context.Background(), filePath, src)
+	if err != nil {
+		t.Fatalf("ParseContent failed: %v", err)
+	}
+	if len(definitions) != 2 || definitions[1].Name != "Flush" || definitions[1].Comment != "Flush writes pending edits" {
+		t.Fatalf("Unexpected definitions %+v", definitions)
+	}
+	if definitions[1].Build != "windows" {
+		t.Errorf("Expected the build constraint of the file name, got %q", definitions[1].Build)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&GoParser{}).ParseContent(ctx, filePath, src); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled parse to fail with context.Canceled, got %v", err)
+	}
+}
+
+func TestGoParser_SyntaxErrors(t *testing.T) {
+	src := []byte("package broken\n\n// Good is fine\nfunc Good() int { return 1 }\n\nfunc Bad( {\n}\n")
+
+	definitions, err := (&GoParser{}).ParseContent(context.Background(), "broken.go", src)
+	var partial *PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("Expected a PartialError, got %v", err)
+	}
+	if d := partial.Diagnostics[0]; d.Severity != "error" || d.Line != 6 || d.Message == "" {
+		t.Errorf("Unexpected diagnostic %+v", d)
+	}
+	if len(definitions) < 2 || definitions[1].Name != "Good" || definitions[1].Comment != "Good is fine" {
+		t.Errorf("Expected the declarations before the error, got %+v", definitions)
+	}
+
+	// Nothing is mapped past a missing package clause
+	definitions, err = (&GoParser{}).ParseContent(context.Background(), "nopkg.go", []byte("func main() {}\n"))
+	if !errors.As(err, &partial) || len(definitions) != 0 {
+		t.Errorf("Expected only diagnostics, got %+v, %v", definitions, err)
+	}
+}
+
+func TestMergeGoPackages(t *testing.T) {
+	tempDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(tempDir, "go.mod"), []byte("module example.com/app\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+	pkgDir := filepath.Join(tempDir, "internal", "store")
+	if err := os.MkdirAll(pkgDir, 0755); err != nil {
+		t.Fatalf("Failed to create package dir: %v", err)
+	}
+	sources := map[string]string{
+		"store.go": "// Package store persists records.\npackage store\n\nfunc Open() {}\n",
+		"doc.go":   "// Package store keeps records on disk.\npackage store\n",
+		"cache.go": "package store\n\nfunc Get() {}\n",
+	}
+	var files []types.FileMap
+	for _, name := range []string{"cache.go", "doc.go", "store.go"} {
+		path := filepath.Join(pkgDir, name)
+		if err := os.WriteFile(path, []byte(sources[name]), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		defs, err := (&GoParser{}).Parse(path)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		files = append(files, types.FileMap{Path: path, Language: "go", Definitions: defs})
+	}
+
+	MergeGoPackages(files)
+
+	var packages []types.Definition
+	for _, f := range files {
+		if f.Package != "example.com/app/internal/store" {
+			t.Errorf("%s: unexpected package %q", f.Path, f.Package)
+		}
+		for _, d := range f.Definitions {
+			if d.Type == "package" {
+				packages = append(packages, d)
+				if filepath.Base(f.Path) != "doc.go" {
+					t.Errorf("Expected the package definition in doc.go, found it in %s", f.Path)
+				}
+			}
+		}
+	}
+	if len(packages) != 1 {
+		t.Fatalf("Expected 1 package definition, got %d", len(packages))
+	}
+	pkg := packages[0]
+	if pkg.QualifiedName != "example.com/app/internal/store" {
+		t.Errorf("Unexpected import path %q", pkg.QualifiedName)
+	}
+	if want := "Package store keeps records on disk.\n\nPackage store persists records."; pkg.Comment != want {
+		t.Errorf("Expected merged doc %q, got %q", want, pkg.Comment)
+	}
+	if len(pkg.Files) != 3 {
+		t.Errorf("Expected 3 files, got %v", pkg.Files)
+	}
+}
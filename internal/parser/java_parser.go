@@ -0,0 +1,197 @@
+package parser
+
+import (
+	"context"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/java"
+
+	"codemap/internal/types"
+)
+
+// JavaParser implements the Parser interface for Java files
+type JavaParser struct{}
+
+// Parse reads filePath and extracts its definitions with ParseContent
+func (p *JavaParser) Parse(filePath string) ([]types.Definition, error) {
+	return parseFile(p, filePath)
+}
+
+// ParseContent extracts definitions from Java source using AST parsing.
+// Top-level types are nested under the package declaration, so qualified
+// names are fully qualified class names.
+func (p *JavaParser) ParseContent(ctx context.Context, filePath string, src []byte) ([]types.Definition, error) {
+	parser := sitter.NewParser()
+	parser.SetLanguage(java.GetLanguage())
+	tree, err := parser.ParseCtx(ctx, nil, src)
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []types.Definition
+	var pkg *types.Definition
+
+	root := tree.RootNode()
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		child := root.NamedChild(i)
+		if child.Type() == "package_declaration" {
+			pkg = extractJavaPackage(child, src, filePath)
+			definitions = append(definitions, *pkg)
+			break
+		}
+	}
+
+	walkJavaTree(root, src, filePath, pkg, &definitions)
+
+	return definitions, syntaxErrors(tree.RootNode(), src)
+}
+
+func walkJavaTree(node *sitter.Node, src []byte, filePath string, parent *types.Definition, definitions *[]types.Definition) {
+	var def *types.Definition
+	switch node.Type() {
+	case "class_declaration":
+		def = extractJavaDeclaration(node, "class", src, filePath)
+	case "interface_declaration":
+		def = extractJavaDeclaration(node, "interface", src, filePath)
+	case "enum_declaration":
+		def = extractJavaDeclaration(node, "enum", src, filePath)
+	case "record_declaration":
+		def = extractJavaDeclaration(node, "record", src, filePath)
+	case "annotation_type_declaration":
+		def = extractJavaDeclaration(node, "annotation", src, filePath)
+	case "method_declaration", "annotation_type_element_declaration":
+		def = extractJavaDeclaration(node, "method", src, filePath)
+	case "constructor_declaration", "compact_constructor_declaration":
+		def = extractJavaDeclaration(node, "constructor", src, filePath)
+	}
+	if def != nil {
+		linkParent(def, parent)
+		*definitions = append(*definitions, *def)
+		parent = def
+	}
+
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		walkJavaTree(child, src, filePath, parent, definitions)
+	}
+}
+
+// extractJavaPackage builds the definition for a package declaration
+func extractJavaPackage(node *sitter.Node, src []byte, filePath string) *types.Definition {
+	var name string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if child.Type() == "scoped_identifier" || child.Type() == "identifier" {
+			name = child.Content(src)
+		}
+	}
+	decl := normalizeWhitespace(node.Content(src))
+	return &types.Definition{
+		Type:       "package",
+		Name:       name,
+		Line:       int(node.StartPoint().Row) + 1,
+		LineEnd:    int(node.EndPoint().Row) + 1,
+		Id:         computeId(filePath, name, decl),
+		Definition: decl,
+		Comment:    extractJavadoc(node, src),
+	}
+}
+
+// extractJavaDeclaration builds a definition for a type or member
+// declaration. Types are reduced to their header since members are emitted
+// separately; enums keep their constants.
+func extractJavaDeclaration(node *sitter.Node, defType string, src []byte, filePath string) *types.Definition {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil
+	}
+	name := nameNode.Content(src)
+	header := normalizeWhitespace(strings.TrimSuffix(headerText(node, src), ";"))
+
+	def := types.Definition{
+		Type:    defType,
+		Name:    name,
+		Line:    int(node.StartPoint().Row) + 1,
+		LineEnd: int(node.EndPoint().Row) + 1,
+		Id:      computeId(filePath, name, header),
+		Comment: extractJavadoc(node, src),
+	}
+	def.Modifiers, def.Annotations = javaModifiers(node, src)
+
+	switch defType {
+	case "method", "constructor":
+		def.Signature = header
+	case "enum":
+		def.Definition = header + javaEnumConstants(node, src)
+	default:
+		def.Definition = header
+	}
+	return &def
+}
+
+// javaModifiers splits a declaration's modifiers into keywords and annotations
+func javaModifiers(node *sitter.Node, src []byte) ([]string, []string) {
+	var modifiers, annotations []string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if child.Type() != "modifiers" {
+			continue
+		}
+		for j := 0; j < int(child.ChildCount()); j++ {
+			mod := child.Child(j)
+			if mod.Type() == "annotation" || mod.Type() == "marker_annotation" {
+				annotations = append(annotations, normalizeWhitespace(mod.Content(src)))
+			} else if mod.Type() != "line_comment" && mod.Type() != "block_comment" {
+				modifiers = append(modifiers, mod.Content(src))
+			}
+		}
+	}
+	return modifiers, annotations
+}
+
+// javaEnumConstants renders the constants of an enum body as " { A, B }"
+func javaEnumConstants(node *sitter.Node, src []byte) string {
+	body := node.ChildByFieldName("body")
+	if body == nil {
+		return ""
+	}
+	var constants []string
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		if child := body.NamedChild(i); child.Type() == "enum_constant" {
+			if name := child.ChildByFieldName("name"); name != nil {
+				constants = append(constants, name.Content(src))
+			}
+		}
+	}
+	if len(constants) == 0 {
+		return ""
+	}
+	return " { " + strings.Join(constants, ", ") + " }"
+}
+
+// extractJavadoc returns the cleaned /** */ comment directly above node
+func extractJavadoc(node *sitter.Node, src []byte) string {
+	prev := node.PrevNamedSibling()
+	if prev == nil || prev.Type() != "block_comment" {
+		return ""
+	}
+	text := prev.Content(src)
+	if !strings.HasPrefix(text, "/**") {
+		return ""
+	}
+	return cleanDocBlock(text)
+}
+
+// cleanDocBlock strips the /** */ delimiters and leading asterisks from a
+// documentation block comment
+func cleanDocBlock(text string) string {
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "/**"), "*/")
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "*")
+		lines = append(lines, strings.TrimPrefix(line, " "))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJavaParser_Parse(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "Shape.java")
+	testContent := `package com.example.shapes;
+
+/**
+ * A drawable shape.
+ */
+@Deprecated
+public abstract class Shape {
+    /**
+     * Creates a shape.
+     */
+    protected Shape() {}
+
+    @Override
+    public static double area(int scale) throws IOException {
+        return 0;
+    }
+
+    enum Kind { CIRCLE, SQUARE }
+}
+
+interface Visitor {
+    void visit(Shape shape);
+}
+
+record Point(int x, int y) {}
+`
+	err := os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	parser := &JavaParser{}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []struct {
+		name      string
+		typ       string
+		qualified string
+	}{
+		{"com.example.shapes", "package", ""},
+		{"Shape", "class", "com.example.shapes.Shape"},
+		{"Shape", "constructor", "com.example.shapes.Shape.Shape"},
+		{"area", "method", "com.example.shapes.Shape.area"},
+		{"Kind", "enum", "com.example.shapes.Shape.Kind"},
+		{"Visitor", "interface", "com.example.shapes.Visitor"},
+		{"visit", "method", "com.example.shapes.Visitor.visit"},
+		{"Point", "record", "com.example.shapes.Point"},
+	}
+
+	if len(definitions) != len(expected) {
+		t.Fatalf("Expected %d definitions, got %d", len(expected), len(definitions))
+	}
+
+	for i, exp := range expected {
+		if definitions[i].Name != exp.name {
+			t.Errorf("Definition %d: expected name %s, got %s", i, exp.name, definitions[i].Name)
+		}
+		if definitions[i].Type != exp.typ {
+			t.Errorf("Definition %d: expected type %s, got %s", i, exp.typ, definitions[i].Type)
+		}
+		if definitions[i].QualifiedName != exp.qualified {
+			t.Errorf("Definition %d: expected qualified name %q, got %q", i, exp.qualified, definitions[i].QualifiedName)
+		}
+	}
+
+	class := definitions[1]
+	if class.Comment != "A drawable shape." {
+		t.Errorf("Expected Javadoc comment, got %q", class.Comment)
+	}
+	if len(class.Annotations) != 1 || class.Annotations[0] != "@Deprecated" {
+		t.Errorf("Expected @Deprecated annotation, got %v", class.Annotations)
+	}
+
+	method := definitions[3]
+	if len(method.Modifiers) != 2 || method.Modifiers[0] != "public" || method.Modifiers[1] != "static" {
+		t.Errorf("Expected public static modifiers, got %v", method.Modifiers)
+	}
+	if want := "@Override public static double area(int scale) throws IOException"; method.Signature != want {
+		t.Errorf("Expected signature %q, got %q", want, method.Signature)
+	}
+	if want := "enum Kind { CIRCLE, SQUARE }"; definitions[4].Definition != want {
+		t.Errorf("Expected enum definition %q, got %q", want, definitions[4].Definition)
+	}
+}
@@ -1,7 +1,7 @@
 package parser
 
 import (
-	"os"
+	"context"
 	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
@@ -10,51 +10,80 @@ import (
 	"codemap/internal/types"
 )
 
-// JSParser implements the Parser interface for JavaScript and TypeScript files
+// JSParser implements the Parser interface for JavaScript files
 type JSParser struct{}
 
-// Parse extracts definitions from a JS/TS source file using AST parsing
+// Parse reads filePath and extracts its definitions with ParseContent
 func (p *JSParser) Parse(filePath string) ([]types.Definition, error) {
-	src, err := os.ReadFile(filePath)
+	return parseFile(p, filePath)
+}
+
+// ParseContent extracts definitions from JS source using AST parsing. The
+// source may be embedded in filePath, such as the script block of a
+// single-file component.
+func (p *JSParser) ParseContent(ctx context.Context, filePath string, src []byte) ([]types.Definition, error) {
+	tree, err := p.parseTree(ctx, src)
 	if err != nil {
 		return nil, err
 	}
+	return p.mapTree(tree, src, filePath)
+}
 
+// parseTree parses JS source into a syntax tree
+func (p *JSParser) parseTree(ctx context.Context, src []byte) (*sitter.Tree, error) {
 	parser := sitter.NewParser()
 	parser.SetLanguage(javascript.GetLanguage())
-	tree := parser.Parse(nil, src)
+	return parser.ParseCtx(ctx, nil, src)
+}
 
+// mapTree extracts the definitions of a tree returned by parseTree
+func (p *JSParser) mapTree(tree *sitter.Tree, src []byte, filePath string) ([]types.Definition, error) {
 	lines := strings.Split(string(src), "\n")
 	var definitions []types.Definition
 
-	walkTree(tree.RootNode(), src, lines, filePath, &definitions)
+	walkTree(tree.RootNode(), src, lines, filePath, nil, nil, &definitions)
 
-	return definitions, nil
+	return definitions, syntaxErrors(tree.RootNode(), src)
 }
 
-func walkTree(node *sitter.Node, src []byte, lines []string, filePath string, definitions *[]types.Definition) {
+// jsNodeExtractor builds a definition for a node that only a grammar
+// extending JavaScript has, such as a TypeScript interface, or returns nil
+type jsNodeExtractor func(node *sitter.Node, src []byte, lines []string, filePath string) *types.Definition
+
+// walkTree maps the JavaScript definitions under node. Nodes the JavaScript
+// grammar does not have are handed to extra, when it is set.
+func walkTree(node *sitter.Node, src []byte, lines []string, filePath string, extra jsNodeExtractor, parent *types.Definition, definitions *[]types.Definition) {
+	var def *types.Definition
 	switch node.Type() {
 	case "function_declaration":
-		if def := extractFunction(node, src, lines, filePath); def != nil {
-			*definitions = append(*definitions, *def)
-		}
-	case "arrow_function":
-		if def := extractArrowFunction(node, src, lines, filePath); def != nil {
-			*definitions = append(*definitions, *def)
-		}
-	case "class_declaration":
-		if def := extractClass(node, src, lines, filePath); def != nil {
-			*definitions = append(*definitions, *def)
-		}
+		def = extractFunction(node, src, lines, filePath)
+	case "arrow_function", "function_expression":
+		def = extractFunctionExpression(node, src, lines, filePath)
+	case "class_declaration", "class":
+		def = extractClass(node, src, lines, filePath)
 	case "method_definition":
-		if def := extractMethod(node, src, lines, filePath); def != nil {
-			*definitions = append(*definitions, *def)
+		def = extractMethod(node, src, lines, filePath)
+	case "variable_declarator", "assignment_expression":
+		def = extractObject(node, src, lines, filePath)
+	default:
+		if extra != nil {
+			def = extra(node, src, lines, filePath)
 		}
 	}
+	if def != nil {
+		markComponent(def, node, src)
+		parseJSDocTags(def.Comment, def)
+		if def.Export == "" && def.Type != "method" {
+			def.Export = jsExportStatus(node, def.Name, src)
+		}
+		linkParent(def, parent)
+		*definitions = append(*definitions, *def)
+		parent = def
+	}
 
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
-		walkTree(child, src, lines, filePath, definitions)
+		walkTree(child, src, lines, filePath, extra, parent, definitions)
 	}
 }
 
@@ -64,91 +93,394 @@ func extractFunction(node *sitter.Node, src []byte, lines []string, filePath str
 		return nil
 	}
 	name := string(src[nameNode.StartByte():nameNode.EndByte()])
-	sig := string(src[node.StartByte():node.EndByte()])
+	sig := signatureText(node, node, src)
 	line := int(node.StartPoint().Row) + 1
-	comment := extractJSComment(lines, line-1)
+	comment := extractJSComment(lines, declarationStartRow(node))
 	def := types.Definition{
 		Type:      "function",
 		Name:      name,
 		Line:       line,
 		LineEnd:    int(node.EndPoint().Row) + 1,
 		Id:        computeId(filePath, name, sig),
-		Signature: normalizeWhitespace(sig),
+		Signature: sig,
 		Comment:   comment,
 	}
 	return &def
 }
 
-func extractArrowFunction(node *sitter.Node, src []byte, lines []string, filePath string) *types.Definition {
+// extractFunctionExpression builds a definition for a function expression
+// or arrow function named by what it is bound to: a variable, an object
+// key, a class field, a CommonJS export or a default export
+func extractFunctionExpression(node *sitter.Node, src []byte, lines []string, filePath string) *types.Definition {
+	name, decl, defType, export := functionBinding(node, src)
+	if name == "" {
+		return nil
+	}
+	sig := signatureText(decl, node, src)
+	line := int(decl.StartPoint().Row) + 1
+	comment := extractJSComment(lines, declarationStartRow(decl))
+	def := types.Definition{
+		Type:      defType,
+		Name:      name,
+		Line:      line,
+		LineEnd:   int(decl.EndPoint().Row) + 1,
+		Id:        computeId(filePath, name, sig),
+		Signature: sig,
+		Comment:   comment,
+		Export:    export,
+	}
+	return &def
+}
+
+// functionBinding returns the name a function expression is bound to, the
+// node declaring the binding, the definition type and, when the binding
+// itself exports the function, its export status. The name is "" for
+// functions that are only passed around, such as callbacks.
+func functionBinding(node *sitter.Node, src []byte) (string, *sitter.Node, string, string) {
+	ownName := func(fallback string) string {
+		if n := node.ChildByFieldName("name"); n != nil {
+			return n.Content(src)
+		}
+		return fallback
+	}
+
+	if declarator := arrowDeclarator(node, src); declarator != nil {
+		nameNode := declarator.ChildByFieldName("name")
+		decl := declarator.Parent()
+		if nameNode == nil || nameNode.Type() != "identifier" || decl == nil {
+			return "", nil, "", ""
+		}
+		name := nameNode.Content(src)
+		return name, decl, "function", jsExportStatus(decl, name, src)
+	}
+
 	parent := node.Parent()
-	if parent != nil && parent.Type() == "variable_declarator" {
-		nameNode := parent.ChildByFieldName("name")
-		if nameNode != nil {
-			name := string(src[nameNode.StartByte():nameNode.EndByte()])
-			declNode := parent.Parent()
-			if declNode != nil && declNode.Type() == "lexical_declaration" {
-				sig := string(src[declNode.StartByte():declNode.EndByte()])
-				line := int(declNode.StartPoint().Row) + 1
-				comment := extractJSComment(lines, line-1)
-				def := types.Definition{
-					Type:      "function",
-					Name:      name,
-					Line:       line,
-					LineEnd:    int(declNode.EndPoint().Row) + 1,
-					Id:        computeId(filePath, name, sig),
-					Signature: normalizeWhitespace(sig),
-					Comment:   comment,
+	if parent == nil {
+		return "", nil, "", ""
+	}
+	switch parent.Type() {
+	case "pair":
+		key := parent.ChildByFieldName("key")
+		if key == nil || parent.ChildByFieldName("value") != node {
+			break
+		}
+		name := strings.Trim(key.Content(src), `"'`)
+		if isModuleExportsMember(parent, src) {
+			return name, parent, "function", "commonjs"
+		}
+		return name, parent, "method", ""
+	case "field_definition", "public_field_definition":
+		key := parent.ChildByFieldName("property")
+		if key == nil {
+			key = parent.ChildByFieldName("name")
+		}
+		if key != nil {
+			return key.Content(src), parent, "method", ""
+		}
+	case "assignment_expression":
+		target, ok := commonJSTarget(parent, src)
+		if !ok || parent.ChildByFieldName("right") != node {
+			break
+		}
+		if target == "" {
+			target = ownName("exports")
+		}
+		decl := parent
+		if stmt := parent.Parent(); stmt != nil && stmt.Type() == "expression_statement" {
+			decl = stmt
+		}
+		return target, decl, "function", "commonjs"
+	case "export_statement":
+		return ownName("default"), node, "function", "default"
+	}
+	return "", nil, "", ""
+}
+
+// extractObject builds a definition for an object literal bound to a
+// variable or a CommonJS export when it holds functions, so that they are
+// mapped as its methods. The definition lists the object's keys.
+func extractObject(node *sitter.Node, src []byte, lines []string, filePath string) *types.Definition {
+	var name, header string
+	var value, decl *sitter.Node
+	if node.Type() == "variable_declarator" {
+		nameNode := node.ChildByFieldName("name")
+		value = node.ChildByFieldName("value")
+		decl = node.Parent()
+		if nameNode == nil || nameNode.Type() != "identifier" || decl == nil || decl.ChildCount() == 0 {
+			return nil
+		}
+		name = nameNode.Content(src)
+		header = decl.Child(0).Content(src) + " " + name
+	} else {
+		target, ok := commonJSTarget(node, src)
+		if !ok || target == "" {
+			return nil
+		}
+		name = target
+		value = node.ChildByFieldName("right")
+		decl = node
+		header = node.ChildByFieldName("left").Content(src)
+	}
+	if value == nil || value.Type() != "object" || !hasFunctionMember(value) {
+		return nil
+	}
+
+	var keys []string
+	for i := 0; i < int(value.NamedChildCount()); i++ {
+		member := value.NamedChild(i)
+		switch member.Type() {
+		case "pair":
+			if key := member.ChildByFieldName("key"); key != nil {
+				keys = append(keys, strings.Trim(key.Content(src), `"'`))
+			}
+		case "method_definition":
+			if key := member.ChildByFieldName("name"); key != nil {
+				keys = append(keys, key.Content(src))
+			}
+		case "shorthand_property_identifier":
+			keys = append(keys, member.Content(src))
+		}
+	}
+	definition := header + " = { " + strings.Join(keys, ", ") + " }"
+
+	def := types.Definition{
+		Type:       "object",
+		Name:       name,
+		Line:       int(decl.StartPoint().Row) + 1,
+		LineEnd:    int(decl.EndPoint().Row) + 1,
+		Id:         computeId(filePath, name, definition),
+		Definition: definition,
+		Comment:    extractJSComment(lines, declarationStartRow(decl)),
+	}
+	if node.Type() == "variable_declarator" {
+		def.Export = jsExportStatus(decl, name, src)
+	} else {
+		def.Export = "commonjs"
+	}
+	return &def
+}
+
+// hasFunctionMember reports whether an object literal has a method or a
+// key bound to a function
+func hasFunctionMember(object *sitter.Node) bool {
+	for i := 0; i < int(object.NamedChildCount()); i++ {
+		member := object.NamedChild(i)
+		switch member.Type() {
+		case "method_definition":
+			return true
+		case "pair":
+			if value := member.ChildByFieldName("value"); value != nil {
+				switch value.Type() {
+				case "arrow_function", "function_expression":
+					return true
 				}
-				return &def
 			}
 		}
 	}
-	return nil
+	return false
+}
+
+// commonJSTarget reports whether an assignment exports a value through
+// module.exports or exports, and returns the exported name, or "" when the
+// assignment replaces module.exports as a whole
+func commonJSTarget(assign *sitter.Node, src []byte) (string, bool) {
+	left := assign.ChildByFieldName("left")
+	if left == nil || left.Type() != "member_expression" {
+		return "", false
+	}
+	if left.Content(src) == "module.exports" {
+		return "", true
+	}
+	object, property := left.ChildByFieldName("object"), left.ChildByFieldName("property")
+	if object == nil || property == nil {
+		return "", false
+	}
+	if o := object.Content(src); o == "exports" || o == "module.exports" {
+		return property.Content(src), true
+	}
+	return "", false
+}
+
+// isModuleExportsMember reports whether member belongs to an object literal
+// assigned to module.exports, whose functions are exported directly
+func isModuleExportsMember(member *sitter.Node, src []byte) bool {
+	object := member.Parent()
+	if object == nil || object.Type() != "object" {
+		return false
+	}
+	assign := object.Parent()
+	if assign == nil || assign.Type() != "assignment_expression" {
+		return false
+	}
+	target, ok := commonJSTarget(assign, src)
+	return ok && target == ""
 }
 
 func extractClass(node *sitter.Node, src []byte, lines []string, filePath string) *types.Definition {
 	nameNode := node.ChildByFieldName("name")
-	if nameNode == nil {
+	name := "default"
+	if nameNode != nil {
+		name = string(src[nameNode.StartByte():nameNode.EndByte()])
+	} else if parent := node.Parent(); parent == nil || parent.Type() != "export_statement" {
+		// Only anonymous classes that are default exports have a name
 		return nil
 	}
-	name := string(src[nameNode.StartByte():nameNode.EndByte()])
-	definition := string(src[node.StartByte():node.EndByte()])
+	definition := classSummary(node, src)
+	if parent := node.Parent(); parent != nil && parent.Type() == "export_statement" {
+		// Decorators of an exported class are attached to the export statement
+		var decorators []string
+		for i := 0; i < int(parent.NamedChildCount()); i++ {
+			if child := parent.NamedChild(i); child.Type() == "decorator" {
+				decorators = append(decorators, normalizeWhitespace(child.Content(src)))
+			}
+		}
+		if len(decorators) > 0 {
+			definition = strings.Join(decorators, " ") + " " + definition
+		}
+	}
 	line := int(node.StartPoint().Row) + 1
-	comment := extractJSComment(lines, line-1)
+	comment := extractJSComment(lines, declarationStartRow(node))
 	def := types.Definition{
 		Type:       "type",
 		Name:       name,
 		Line:        line,
 		LineEnd:     int(node.EndPoint().Row) + 1,
 		Id:         computeId(filePath, name, definition),
-		Definition: normalizeWhitespace(definition),
+		Definition: definition,
 		Comment:    comment,
 	}
 	return &def
 }
 
+// classSummary renders a class as its header followed by the signatures of
+// its members, as in "class A extends B { x: number; run(n) }". Bodies and
+// field initializers are left out.
+func classSummary(node *sitter.Node, src []byte) string {
+	header := normalizeWhitespace(headerText(node, src))
+	body := node.ChildByFieldName("body")
+	if body == nil {
+		return header
+	}
+
+	var members, decorators []string
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		member := body.NamedChild(i)
+		var sig string
+		switch member.Type() {
+		case "comment", "class_static_block":
+			continue
+		case "decorator":
+			// Member decorators precede the member they decorate
+			decorators = append(decorators, normalizeWhitespace(member.Content(src)))
+			continue
+		case "method_definition":
+			sig = signatureText(member, member, src)
+		case "field_definition", "public_field_definition":
+			value := member.ChildByFieldName("value")
+			switch {
+			case value == nil:
+				sig = signatureText(member, member, src)
+			case value.Type() == "arrow_function" || value.Type() == "function_expression":
+				sig = signatureText(member, value, src)
+			default:
+				sig = strings.TrimSpace(string(src[member.StartByte():value.StartByte()]))
+				sig = normalizeWhitespace(strings.TrimSpace(strings.TrimSuffix(sig, "=")))
+			}
+		default:
+			sig = signatureText(member, member, src)
+		}
+		if len(decorators) > 0 {
+			sig = strings.Join(decorators, " ") + " " + sig
+			decorators = nil
+		}
+		members = append(members, sig)
+	}
+	if len(members) == 0 {
+		return header + " {}"
+	}
+	return header + " { " + strings.Join(members, "; ") + " }"
+}
+
 func extractMethod(node *sitter.Node, src []byte, lines []string, filePath string) *types.Definition {
 	nameNode := node.ChildByFieldName("name")
 	if nameNode == nil {
 		return nil
 	}
 	name := string(src[nameNode.StartByte():nameNode.EndByte()])
-	sig := string(src[node.StartByte():node.EndByte()])
+	sig := signatureText(node, node, src)
 	line := int(node.StartPoint().Row) + 1
-	comment := extractJSComment(lines, line-1)
+	comment := extractJSComment(lines, declarationStartRow(node))
 	def := types.Definition{
-		Type:      "function",
+		Type:      "method",
 		Name:      name,
 		Line:       line,
 		LineEnd:    int(node.EndPoint().Row) + 1,
 		Id:        computeId(filePath, name, sig),
-		Signature: normalizeWhitespace(sig),
+		Signature: sig,
 		Comment:   comment,
 	}
+	if isModuleExportsMember(node, src) {
+		def.Type = "function"
+		def.Export = "commonjs"
+	}
 	return &def
 }
 
-// extractJSComment extracts preceding comment lines
+// signatureText returns the normalized source from the start of decl up to
+// the body of the function fn, leaving the body out of the signature. decl
+// may be fn itself or the declaration binding it, such as "const f = ...".
+func signatureText(decl, fn *sitter.Node, src []byte) string {
+	end := decl.EndByte()
+	if body := fn.ChildByFieldName("body"); body != nil {
+		end = body.StartByte()
+	}
+	sig := strings.TrimSpace(string(src[decl.StartByte():end]))
+	return normalizeWhitespace(strings.TrimSpace(strings.TrimSuffix(sig, ";")))
+}
+
+// arrowDeclarator returns the variable declarator an arrow function or
+// function expression is bound to, looking through React's memo and forwardRef wrappers
+func arrowDeclarator(node *sitter.Node, src []byte) *sitter.Node {
+	parent := node.Parent()
+	if parent != nil && parent.Type() == "arguments" {
+		call := parent.Parent()
+		if call == nil || call.Type() != "call_expression" {
+			return nil
+		}
+		fn := call.ChildByFieldName("function")
+		if fn == nil {
+			return nil
+		}
+		if prop := fn.ChildByFieldName("property"); prop != nil {
+			fn = prop
+		}
+		if name := fn.Content(src); name != "memo" && name != "forwardRef" {
+			return nil
+		}
+		parent = call.Parent()
+	}
+	if parent != nil && parent.Type() == "variable_declarator" {
+		return parent
+	}
+	return nil
+}
+
+// declarationStartRow returns the zero-based row where the declaration
+// containing node begins, including any export keyword or decorators that
+// the grammar places on a wrapping node
+func declarationStartRow(node *sitter.Node) int {
+	start := node
+	for parent := start.Parent(); parent != nil; parent = parent.Parent() {
+		if parent.Type() != "export_statement" && parent.Type() != "ambient_declaration" {
+			break
+		}
+		start = parent
+	}
+	return int(start.StartPoint().Row)
+}
+
+// extractJSComment extracts the comment lines preceding a declaration: a
+// /* */ or /** */ block, or a run of // line comments
 func extractJSComment(lines []string, currentIndex int) string {
 	var comments []string
 	for i := currentIndex - 1; i >= 0; i-- {
@@ -157,9 +489,33 @@ func extractJSComment(lines []string, currentIndex int) string {
 			comments = append([]string{strings.TrimPrefix(line, "//")}, comments...)
 		} else if line == "" {
 			continue
+		} else if strings.HasSuffix(line, "*/") && len(comments) == 0 {
+			return extractJSBlockComment(lines, i)
 		} else {
 			break
 		}
 	}
 	return strings.Join(comments, "\n")
 }
+
+// extractJSBlockComment returns the cleaned block comment ending on line
+// end, or "" when the line closes something other than a comment
+func extractJSBlockComment(lines []string, end int) string {
+	for start := end; start >= 0; start-- {
+		open := strings.Index(lines[start], "/*")
+		if open < 0 {
+			continue
+		}
+		if strings.TrimSpace(lines[start][:open]) != "" {
+			// Code precedes the comment on its line
+			return ""
+		}
+		block := strings.Join(lines[start:end+1], "\n")
+		block = strings.TrimSpace(block)
+		if !strings.HasPrefix(block, "/**") {
+			block = "/**" + strings.TrimPrefix(block, "/*")
+		}
+		return cleanDocBlock(block)
+	}
+	return ""
+}
@@ -0,0 +1,191 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJSParser_Parse(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "test.js")
+	testContent := `// Legacy helper
+var legacy = function() {};
+
+module.exports.load = function(path) {};
+exports.save = (path) => {};
+
+const api = {
+    get() {},
+    put: function(value) {},
+};
+
+class Store {
+    handle = () => {};
+}
+
+function internal() {}
+
+export default function () {}
+
+module.exports = { internal, run() {} };
+`
+	err := os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	parser := &JSParser{}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []struct {
+		name   string
+		typ    string
+		parent string
+		export string
+	}{
+		{"legacy", "function", "", ""},
+		{"load", "function", "", "commonjs"},
+		{"save", "function", "", "commonjs"},
+		{"api", "object", "", ""},
+		{"get", "method", "api", ""},
+		{"put", "method", "api", ""},
+		{"Store", "type", "", ""},
+		{"handle", "method", "Store", ""},
+		{"internal", "function", "", "commonjs"},
+		{"default", "function", "", "default"},
+		{"run", "function", "", "commonjs"},
+	}
+
+	if len(definitions) != len(expected) {
+		t.Fatalf("Expected %d definitions, got %d", len(expected), len(definitions))
+	}
+
+	for i, exp := range expected {
+		def := definitions[i]
+		if def.Name != exp.name || def.Type != exp.typ {
+			t.Errorf("Definition %d: expected %s %s, got %s %s", i, exp.typ, exp.name, def.Type, def.Name)
+		}
+		if def.Parent != exp.parent {
+			t.Errorf("Definition %d: expected parent %q, got %q", i, exp.parent, def.Parent)
+		}
+		if def.Export != exp.export {
+			t.Errorf("Definition %d: expected export %q, got %q", i, exp.export, def.Export)
+		}
+	}
+
+	if definitions[0].Comment != " Legacy helper" {
+		t.Errorf("Expected legacy comment, got %q", definitions[0].Comment)
+	}
+	if want := "const api = { get, put }"; definitions[3].Definition != want {
+		t.Errorf("Expected object definition %q, got %q", want, definitions[3].Definition)
+	}
+	if want := "var legacy = function()"; definitions[0].Signature != want {
+		t.Errorf("Expected signature %q, got %q", want, definitions[0].Signature)
+	}
+	if want := "class Store { handle = () => }"; definitions[6].Definition != want {
+		t.Errorf("Expected class definition %q, got %q", want, definitions[6].Definition)
+	}
+	if definitions[4].ParentId != definitions[3].Id {
+		t.Errorf("Expected get to be linked to api")
+	}
+}
+
+func TestJSParser_JSDoc(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "doc.js")
+	testContent := `/**
+ * Fetches a user.
+ * @param {string} id - The user id
+ * @param {{retries: number}} [opts={}] Request options
+ *   applied to every attempt
+ * @returns {Promise<User>} The user
+ * @throws {NotFoundError} When no user has the id
+ * @deprecated Use loadUser instead
+ * @example
+ * fetchUser("42")
+ *   .then(show)
+ */
+function fetchUser(id, opts) {}
+
+/* Plain block comment */
+const noop = () => {};
+`
+	err := os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	parser := &JSParser{}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(definitions) != 2 {
+		t.Fatalf("Expected 2 definitions, got %d", len(definitions))
+	}
+
+	def := definitions[0]
+	if !strings.HasPrefix(def.Comment, "Fetches a user.\n@param") {
+		t.Errorf("Expected JSDoc comment, got %q", def.Comment)
+	}
+	if len(def.Params) != 2 {
+		t.Fatalf("Expected 2 params, got %+v", def.Params)
+	}
+	if p := def.Params[0]; p.Name != "id" || p.Type != "string" || p.Description != "The user id" {
+		t.Errorf("Unexpected first param %+v", p)
+	}
+	if p := def.Params[1]; p.Name != "opts" || p.Type != "{retries: number}" || p.Description != "Request options applied to every attempt" {
+		t.Errorf("Unexpected second param %+v", p)
+	}
+	if def.Returns == nil || def.Returns.Type != "Promise<User>" || def.Returns.Description != "The user" {
+		t.Errorf("Unexpected returns %+v", def.Returns)
+	}
+	if len(def.Raises) != 1 || def.Raises[0].Type != "NotFoundError" {
+		t.Errorf("Unexpected raises %+v", def.Raises)
+	}
+	if def.Deprecated != "Use loadUser instead" {
+		t.Errorf("Unexpected deprecation %q", def.Deprecated)
+	}
+	if len(def.Examples) != 1 || def.Examples[0] != "fetchUser(\"42\")\n  .then(show)" {
+		t.Errorf("Unexpected examples %q", def.Examples)
+	}
+
+	if definitions[1].Comment != "Plain block comment" {
+		t.Errorf("Expected block comment, got %q", definitions[1].Comment)
+	}
+}
+
+func TestJSParser_SyntaxErrors(t *testing.T) {
+	src := []byte("function ok() {}\nfunction bad( {\n  let x = ;\n}\nclass C { m() {} }\n")
+
+	definitions, err := (&JSParser{}).ParseContent(context.Background(), "broken.js", src)
+	var partial *PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("Expected a PartialError, got %v", err)
+	}
+	if d := partial.Diagnostics[0]; d.Severity != "error" || d.Line != 2 || !strings.Contains(d.Message, "function bad(") {
+		t.Errorf("Unexpected diagnostic %+v", d)
+	}
+
+	var names []string
+	for _, def := range definitions {
+		names = append(names, def.Name)
+	}
+	if strings.Join(names, ",") != "ok,C,m" {
+		t.Errorf("Expected the definitions around the error, got %v", names)
+	}
+
+	if _, err := (&JSParser{}).ParseContent(context.Background(), "ok.js", []byte("function ok() {}\n")); err != nil {
+		t.Errorf("Expected no error for a valid file, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"codemap/internal/types"
+)
+
+var jsDocTag = regexp.MustCompile(`^@(\w+)\s*(.*)$`)
+
+// parseJSDocTags maps the @param, @returns, @throws, @deprecated and
+// @example tags of a JSDoc or TSDoc comment onto the structured fields of
+// def. As in JSDoc, a tag runs until the next one, so following lines
+// continue its description; example code keeps its line breaks.
+func parseJSDocTags(doc string, def *types.Definition) {
+	var last *types.DocParam
+	var example *string
+	inDeprecated := false
+	for _, line := range strings.Split(doc, "\n") {
+		trimmed := strings.TrimSpace(line)
+		m := jsDocTag.FindStringSubmatch(trimmed)
+		if m == nil {
+			switch {
+			case example != nil:
+				*example = strings.TrimRight(*example+"\n"+line, " ")
+			case last != nil && trimmed != "":
+				last.Description = strings.TrimSpace(last.Description + " " + trimmed)
+			case inDeprecated && trimmed != "":
+				def.Deprecated = strings.TrimSpace(def.Deprecated + " " + trimmed)
+			}
+			continue
+		}
+
+		last, example, inDeprecated = nil, nil, false
+		rest := m[2]
+		switch m[1] {
+		case "param", "arg", "argument":
+			entry := jsDocEntry(rest, true)
+			def.Params = append(def.Params, entry)
+			last = &def.Params[len(def.Params)-1]
+		case "returns", "return":
+			entry := jsDocEntry(rest, false)
+			def.Returns = &entry
+			last = def.Returns
+		case "throws", "exception":
+			entry := jsDocEntry(rest, false)
+			def.Raises = append(def.Raises, entry)
+			last = &def.Raises[len(def.Raises)-1]
+		case "deprecated":
+			def.Deprecated = strings.TrimSpace(rest)
+			if def.Deprecated == "" {
+				def.Deprecated = "deprecated"
+			}
+			inDeprecated = true
+		case "example":
+			def.Examples = append(def.Examples, rest)
+			example = &def.Examples[len(def.Examples)-1]
+		}
+	}
+	for i := range def.Examples {
+		def.Examples[i] = strings.TrimSpace(def.Examples[i])
+	}
+}
+
+// jsDocEntry parses the text after a tag: an optional {Type}, the name when
+// named is set, and a description with any leading hyphen removed. Optional
+// parameters written as [name] or [name=default] are reduced to name.
+func jsDocEntry(text string, named bool) types.DocParam {
+	var entry types.DocParam
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "{") {
+		// Types may contain braces of their own, as in {{a: number}}
+		depth := 0
+		for i, r := range text {
+			if r == '{' {
+				depth++
+			} else if r == '}' {
+				depth--
+			}
+			if depth == 0 {
+				entry.Type = strings.TrimSpace(text[1:i])
+				text = strings.TrimSpace(text[i+1:])
+				break
+			}
+		}
+	}
+	if named && text != "" {
+		end := strings.IndexAny(text, " \t")
+		if strings.HasPrefix(text, "[") {
+			end = strings.Index(text, "]") + 1
+		}
+		if end <= 0 {
+			end = len(text)
+		}
+		name := strings.Trim(text[:end], "[]")
+		if i := strings.Index(name, "="); i >= 0 {
+			name = name[:i]
+		}
+		entry.Name = strings.TrimSpace(name)
+		text = strings.TrimSpace(text[end:])
+	}
+	entry.Description = strings.TrimSpace(strings.TrimPrefix(text, "-"))
+	return entry
+}
@@ -0,0 +1,286 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"codemap/internal/types"
+)
+
+var hookName = regexp.MustCompile(`^use[A-Z0-9]`)
+
+// markComponent turns def into a "component" definition when node is a
+// React component: a capitalized function returning JSX, or a class that
+// extends Component/PureComponent or renders JSX. Components carry their
+// props type and the hooks they call.
+func markComponent(def *types.Definition, node *sitter.Node, src []byte) {
+	if def.Name == "" || !unicode.IsUpper(rune(def.Name[0])) {
+		return
+	}
+
+	switch node.Type() {
+	case "class_declaration", "abstract_class_declaration":
+		if !isClassComponent(node, src) {
+			return
+		}
+		def.Props = classComponentProps(node, src)
+	case "function_declaration", "arrow_function", "function_expression":
+		body := node.ChildByFieldName("body")
+		if body == nil || !containsJSX(body) {
+			return
+		}
+		def.Props = functionComponentProps(node, src)
+		def.Hooks = collectHooks(body, src, nil)
+		if declarator := arrowDeclarator(node, src); declarator != nil && def.Props == "" {
+			def.Props = declaredComponentProps(declarator, src)
+		}
+	default:
+		return
+	}
+
+	def.Type = "component"
+}
+
+// isClassComponent reports whether a class extends React's Component or
+// PureComponent, or has a render method returning JSX
+func isClassComponent(node *sitter.Node, src []byte) bool {
+	if superclass := classSuperclass(node); superclass != nil {
+		name := superclass.Content(src)
+		if strings.HasSuffix(name, "Component") || strings.HasSuffix(name, "PureComponent") {
+			return true
+		}
+	}
+	body := node.ChildByFieldName("body")
+	if body == nil {
+		return false
+	}
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		member := body.NamedChild(i)
+		if member.Type() != "method_definition" {
+			continue
+		}
+		if name := member.ChildByFieldName("name"); name != nil && name.Content(src) == "render" {
+			if b := member.ChildByFieldName("body"); b != nil && containsJSX(b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// classSuperclass returns the expression a class extends, if any. The JS
+// grammar puts it directly in the class heritage, TS in an extends clause.
+func classSuperclass(node *sitter.Node) *sitter.Node {
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		heritage := node.NamedChild(i)
+		if heritage.Type() != "class_heritage" {
+			continue
+		}
+		for j := 0; j < int(heritage.NamedChildCount()); j++ {
+			child := heritage.NamedChild(j)
+			if child.Type() == "extends_clause" {
+				return child.ChildByFieldName("value")
+			}
+			return child
+		}
+	}
+	return nil
+}
+
+// classComponentProps returns the first type argument of a class
+// component's superclass, as in React.Component<Props, State>
+func classComponentProps(node *sitter.Node, src []byte) string {
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		heritage := node.NamedChild(i)
+		if heritage.Type() != "class_heritage" {
+			continue
+		}
+		for j := 0; j < int(heritage.NamedChildCount()); j++ {
+			clause := heritage.NamedChild(j)
+			if clause.Type() != "extends_clause" {
+				continue
+			}
+			if args := clause.ChildByFieldName("type_arguments"); args != nil && args.NamedChildCount() > 0 {
+				return args.NamedChild(0).Content(src)
+			}
+		}
+	}
+	return ""
+}
+
+// functionComponentProps returns the type annotation of a function
+// component's first parameter
+func functionComponentProps(node *sitter.Node, src []byte) string {
+	params := node.ChildByFieldName("parameters")
+	if params == nil || params.NamedChildCount() == 0 {
+		return ""
+	}
+	param := params.NamedChild(0)
+	if annotation := param.ChildByFieldName("type"); annotation != nil {
+		return strings.TrimSpace(strings.TrimPrefix(annotation.Content(src), ":"))
+	}
+	return ""
+}
+
+// declaredComponentProps returns the props type of a component declared
+// with a typed variable, as in const Bar: React.FC<BarProps> = ...
+func declaredComponentProps(declarator *sitter.Node, src []byte) string {
+	annotation := declarator.ChildByFieldName("type")
+	if annotation == nil || annotation.NamedChildCount() == 0 {
+		return ""
+	}
+	generic := annotation.NamedChild(0)
+	if generic.Type() != "generic_type" {
+		return ""
+	}
+	if args := generic.ChildByFieldName("type_arguments"); args != nil && args.NamedChildCount() > 0 {
+		return args.NamedChild(0).Content(src)
+	}
+	return ""
+}
+
+// containsJSX reports whether node contains a JSX element or fragment
+func containsJSX(node *sitter.Node) bool {
+	switch node.Type() {
+	case "jsx_element", "jsx_self_closing_element", "jsx_fragment":
+		return true
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		if containsJSX(node.NamedChild(i)) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectHooks appends the distinct hooks called within node, such as
+// useState or React.useEffect, in the order they are first called
+func collectHooks(node *sitter.Node, src []byte, hooks []string) []string {
+	if node.Type() == "call_expression" {
+		if fn := node.ChildByFieldName("function"); fn != nil {
+			name := fn.Content(src)
+			if fn.Type() == "member_expression" {
+				if prop := fn.ChildByFieldName("property"); prop != nil {
+					name = prop.Content(src)
+				}
+			}
+			if hookName.MatchString(name) && !containsString(hooks, name) {
+				hooks = append(hooks, name)
+			}
+		}
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		hooks = collectHooks(node.NamedChild(i), src, hooks)
+	}
+	return hooks
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// jsExportStatus returns "default" or "named" when the declaration node
+// named name is exported, either directly by an export statement or later
+// in the file by "export default name" or "export { name }", "commonjs"
+// when it is assigned to module.exports or exports, and "" when it is not
+// exported. Only top-level declarations can be exported by name.
+func jsExportStatus(node *sitter.Node, name string, src []byte) string {
+	parent := node.Parent()
+	if parent != nil && parent.Type() == "export_statement" {
+		if isDefaultExport(parent) {
+			return "default"
+		}
+		return "named"
+	}
+	if parent == nil || parent.Type() != "program" {
+		return ""
+	}
+
+	status := ""
+	for i := 0; i < int(parent.NamedChildCount()); i++ {
+		stmt := parent.NamedChild(i)
+		if stmt.Type() == "expression_statement" && stmt.NamedChildCount() > 0 {
+			if assign := stmt.NamedChild(0); status == "" && exportsByCommonJS(assign, name, src) {
+				status = "commonjs"
+			}
+			continue
+		}
+		if stmt.Type() != "export_statement" {
+			continue
+		}
+		if value := stmt.ChildByFieldName("value"); value != nil && isDefaultExport(stmt) && value.Content(src) == name {
+			return "default"
+		}
+		for j := 0; j < int(stmt.NamedChildCount()); j++ {
+			clause := stmt.NamedChild(j)
+			if clause.Type() != "export_clause" {
+				continue
+			}
+			for k := 0; k < int(clause.NamedChildCount()); k++ {
+				spec := clause.NamedChild(k)
+				if local := spec.ChildByFieldName("name"); local == nil || local.Content(src) != name {
+					continue
+				}
+				if alias := spec.ChildByFieldName("alias"); alias != nil && alias.Content(src) == "default" {
+					return "default"
+				}
+				status = "named"
+			}
+		}
+	}
+	return status
+}
+
+// exportsByCommonJS reports whether an assignment exports the value named
+// name, as in "module.exports = name", "exports.alias = name" or
+// "module.exports = { name }"
+func exportsByCommonJS(assign *sitter.Node, name string, src []byte) bool {
+	if assign.Type() != "assignment_expression" {
+		return false
+	}
+	if _, ok := commonJSTarget(assign, src); !ok {
+		return false
+	}
+	right := assign.ChildByFieldName("right")
+	if right == nil {
+		return false
+	}
+	if right.Type() == "identifier" {
+		return right.Content(src) == name
+	}
+	if right.Type() != "object" {
+		return false
+	}
+	for i := 0; i < int(right.NamedChildCount()); i++ {
+		member := right.NamedChild(i)
+		switch member.Type() {
+		case "shorthand_property_identifier":
+			if member.Content(src) == name {
+				return true
+			}
+		case "pair":
+			if value := member.ChildByFieldName("value"); value != nil && value.Type() == "identifier" && value.Content(src) == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isDefaultExport reports whether an export statement has the default keyword
+func isDefaultExport(stmt *sitter.Node) bool {
+	for i := 0; i < int(stmt.ChildCount()); i++ {
+		if stmt.Child(i).Type() == "default" {
+			return true
+		}
+	}
+	return false
+}
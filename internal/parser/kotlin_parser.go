@@ -0,0 +1,349 @@
+package parser
+
+import (
+	"context"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/kotlin"
+
+	"codemap/internal/types"
+)
+
+// KotlinParser implements the Parser interface for Kotlin files
+type KotlinParser struct{}
+
+// Parse reads filePath and extracts its definitions with ParseContent
+func (p *KotlinParser) Parse(filePath string) ([]types.Definition, error) {
+	return parseFile(p, filePath)
+}
+
+// ParseContent extracts definitions from Kotlin source using AST parsing.
+// Like Java, top-level declarations are nested under the package header,
+// and extension functions are linked to the type they extend.
+func (p *KotlinParser) ParseContent(ctx context.Context, filePath string, src []byte) ([]types.Definition, error) {
+	parser := sitter.NewParser()
+	parser.SetLanguage(kotlin.GetLanguage())
+	tree, err := parser.ParseCtx(ctx, nil, src)
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []types.Definition
+	var pkg *types.Definition
+
+	root := tree.RootNode()
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		child := root.NamedChild(i)
+		if child.Type() == "package_header" {
+			pkg = extractKotlinPackage(child, src, filePath)
+			definitions = append(definitions, *pkg)
+			break
+		}
+	}
+
+	walkKotlinTree(root, src, filePath, pkg, &definitions)
+
+	linkExtensions(definitions)
+
+	return definitions, syntaxErrors(tree.RootNode(), src)
+}
+
+func walkKotlinTree(node *sitter.Node, src []byte, filePath string, parent *types.Definition, definitions *[]types.Definition) {
+	var def *types.Definition
+	switch node.Type() {
+	case "class_declaration":
+		def = extractKotlinDeclaration(node, kotlinClassKind(node), src, filePath)
+	case "object_declaration", "companion_object":
+		def = extractKotlinDeclaration(node, "object", src, filePath)
+	case "type_alias":
+		def = extractKotlinDeclaration(node, "typealias", src, filePath)
+	case "function_declaration":
+		defType := "function"
+		if parent != nil && parent.Type != "package" {
+			defType = "method"
+		}
+		def = extractKotlinDeclaration(node, defType, src, filePath)
+		if def == nil {
+			return
+		}
+		linkParent(def, parent)
+		if receiver := kotlinReceiverType(node, src); receiver != "" {
+			// Extension functions belong to the extended type, which
+			// linkExtensions resolves once all types are known
+			def.Type = "method"
+			def.Parent = receiver
+			def.ParentId = ""
+			def.QualifiedName = receiver + "." + def.Name
+		}
+		def.Id = computeId(filePath, qualifiedNameOf(*def), def.Signature)
+		*definitions = append(*definitions, *def)
+		// Function bodies hold no definitions worth mapping
+		return
+	}
+	if def != nil {
+		linkParent(def, parent)
+		def.Id = computeId(filePath, qualifiedNameOf(*def), def.Definition)
+		*definitions = append(*definitions, *def)
+		parent = def
+	}
+
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		walkKotlinTree(child, src, filePath, parent, definitions)
+	}
+}
+
+// extractKotlinPackage builds the definition for a package header
+func extractKotlinPackage(node *sitter.Node, src []byte, filePath string) *types.Definition {
+	var name string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		if child := node.NamedChild(i); child.Type() == "identifier" {
+			name = child.Content(src)
+		}
+	}
+	decl := "package " + name
+	return &types.Definition{
+		Type:       "package",
+		Name:       name,
+		Line:       int(node.StartPoint().Row) + 1,
+		LineEnd:    int(node.StartPoint().Row) + 1,
+		Id:         computeId(filePath, name, decl),
+		Definition: decl,
+		Comment:    extractKotlinDoc(node, src),
+	}
+}
+
+// kotlinClassKind distinguishes classes, interfaces and enum classes, which
+// share one node type in the grammar
+func kotlinClassKind(node *sitter.Node) string {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		switch node.Child(i).Type() {
+		case "interface":
+			return "interface"
+		case "enum_class_body":
+			return "enum"
+		}
+	}
+	return "class"
+}
+
+// extractKotlinDeclaration builds a definition for a declaration. Types are
+// reduced to their header since members are emitted separately; enums keep
+// their entries. The Id is left to the caller, which knows the qualified
+// name.
+func extractKotlinDeclaration(node *sitter.Node, defType string, src []byte, filePath string) *types.Definition {
+	name := kotlinName(node, src)
+	if name == "" {
+		return nil
+	}
+	header := normalizeWhitespace(kotlinHeader(node, src))
+
+	def := types.Definition{
+		Type:    defType,
+		Name:    name,
+		Line:    int(node.StartPoint().Row) + 1,
+		LineEnd: int(node.EndPoint().Row) + 1,
+		Comment: extractKotlinDoc(node, src),
+	}
+	def.Modifiers, def.Annotations = kotlinModifiers(node, src)
+
+	switch defType {
+	case "function", "method":
+		def.Signature = header
+	case "enum":
+		def.Definition = header + kotlinEnumEntries(node, src)
+	default:
+		def.Definition = header
+	}
+	return &def
+}
+
+// kotlinName returns the declared name of a node. Companion objects without
+// a name are called Companion, as in Kotlin itself.
+func kotlinName(node *sitter.Node, src []byte) string {
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		switch child := node.NamedChild(i); child.Type() {
+		case "type_identifier", "simple_identifier":
+			return child.Content(src)
+		}
+	}
+	if node.Type() == "companion_object" {
+		return "Companion"
+	}
+	return ""
+}
+
+// kotlinHeader returns the source of a declaration up to its body. The
+// grammar has no body field, so the body is found by node type.
+func kotlinHeader(node *sitter.Node, src []byte) string {
+	end := node.EndByte()
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		switch child := node.NamedChild(i); child.Type() {
+		case "class_body", "enum_class_body", "function_body":
+			end = child.StartByte()
+		}
+	}
+	header := strings.TrimSpace(string(src[node.StartByte():end]))
+	return strings.TrimSpace(strings.TrimSuffix(header, "="))
+}
+
+// kotlinReceiverType returns the receiver type of an extension function,
+// without generic arguments, or "" for other functions
+func kotlinReceiverType(node *sitter.Node, src []byte) string {
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		switch child.Type() {
+		case "simple_identifier":
+			// The receiver precedes the function name
+			return ""
+		case "user_type", "nullable_type":
+			name := child.Content(src)
+			if j := strings.IndexAny(name, "<?"); j >= 0 {
+				name = name[:j]
+			}
+			return name
+		}
+	}
+	return ""
+}
+
+// kotlinModifiers splits a declaration's modifiers into keywords and annotations
+func kotlinModifiers(node *sitter.Node, src []byte) ([]string, []string) {
+	var modifiers, annotations []string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if child.Type() != "modifiers" {
+			continue
+		}
+		for j := 0; j < int(child.NamedChildCount()); j++ {
+			mod := child.NamedChild(j)
+			if mod.Type() == "annotation" {
+				annotations = append(annotations, normalizeWhitespace(mod.Content(src)))
+			} else if mod.Type() != "line_comment" && mod.Type() != "multiline_comment" {
+				modifiers = append(modifiers, mod.Content(src))
+			}
+		}
+	}
+	return modifiers, annotations
+}
+
+// kotlinEnumEntries renders the entries of an enum class body as " { A, B }"
+func kotlinEnumEntries(node *sitter.Node, src []byte) string {
+	var entries []string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		body := node.NamedChild(i)
+		if body.Type() != "enum_class_body" {
+			continue
+		}
+		for j := 0; j < int(body.NamedChildCount()); j++ {
+			if entry := body.NamedChild(j); entry.Type() == "enum_entry" {
+				entries = append(entries, kotlinName(entry, src))
+			}
+		}
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+	return " { " + strings.Join(entries, ", ") + " }"
+}
+
+// extractKotlinDoc returns the cleaned KDoc block directly above node. The
+// grammar attaches comments that follow the package header or imports to
+// those nodes, so their last child is checked as well.
+func extractKotlinDoc(node *sitter.Node, src []byte) string {
+	prev := node.PrevNamedSibling()
+	if prev != nil && (prev.Type() == "package_header" || prev.Type() == "import_list") && prev.NamedChildCount() > 0 {
+		prev = prev.NamedChild(int(prev.NamedChildCount()) - 1)
+	}
+	if prev == nil || prev.Type() != "multiline_comment" {
+		return ""
+	}
+	text := prev.Content(src)
+	if !strings.HasPrefix(text, "/**") {
+		return ""
+	}
+	return cleanDocBlock(text)
+}
+
+// linkExtensions resolves the parent of extension functions and Swift
+// extensions to the extended type when it is declared in the same file.
+// LinkExtensions links the rest once all files are parsed.
+func linkExtensions(definitions []types.Definition) {
+	index := newExtendedTypes()
+	for _, def := range definitions {
+		index.add(def)
+	}
+	for i := range definitions {
+		index.link(&definitions[i])
+	}
+}
+
+// LinkExtensions links the Kotlin extension functions and Swift extensions
+// whose extended type is declared in another file of the same language
+func LinkExtensions(files []types.FileMap) {
+	for _, lang := range []string{"kotlin", "swift"} {
+		index := newExtendedTypes()
+		for _, f := range files {
+			if f.Language != lang {
+				continue
+			}
+			for _, def := range f.Definitions {
+				index.add(def)
+			}
+		}
+		for fi := range files {
+			if files[fi].Language != lang {
+				continue
+			}
+			for di := range files[fi].Definitions {
+				index.link(&files[fi].Definitions[di])
+			}
+		}
+	}
+}
+
+// extendedTypes indexes the types an extension can extend, by qualified
+// name and by bare name
+type extendedTypes struct {
+	byQualified map[string]types.Definition
+	byName      map[string]types.Definition
+}
+
+func newExtendedTypes() *extendedTypes {
+	return &extendedTypes{
+		byQualified: make(map[string]types.Definition),
+		byName:      make(map[string]types.Definition),
+	}
+}
+
+// add indexes def when it is a type
+func (t *extendedTypes) add(def types.Definition) {
+	switch def.Type {
+	case "class", "struct", "interface", "enum", "object", "protocol", "actor", "typealias":
+		t.byQualified[qualifiedNameOf(def)] = def
+		if _, ok := t.byName[def.Name]; !ok {
+			t.byName[def.Name] = def
+		}
+	}
+}
+
+// link resolves the parent of an unlinked definition to the indexed type it
+// names, matching on qualified name first and then on the bare type name
+func (t *extendedTypes) link(def *types.Definition) {
+	if def.ParentId != "" || def.Parent == "" {
+		return
+	}
+	target, ok := t.byQualified[def.Parent]
+	if !ok {
+		target, ok = t.byName[def.Parent]
+	}
+	if !ok {
+		return
+	}
+	def.Parent = qualifiedNameOf(target)
+	def.ParentId = target.Id
+	if def.QualifiedName != "" {
+		def.QualifiedName = def.Parent + "." + def.Name
+	}
+}
@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"codemap/internal/types"
+)
+
+func TestKotlinParser_Parse(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "Shapes.kt")
+	testContent := `package com.example.shapes
+
+/**
+ * A point.
+ */
+data class Point(val x: Int, val y: Int) : Shape {
+    override fun area(scale: Int): Double = 0.0
+    companion object { fun origin() = Point(0, 0) }
+}
+
+object Registry
+interface Shape { fun area(scale: Int): Double }
+enum class Color { RED, GREEN }
+
+fun Point.norm(): Double { return 0.0 }
+`
+	err := os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	parser := &KotlinParser{}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []struct {
+		name      string
+		typ       string
+		qualified string
+	}{
+		{"com.example.shapes", "package", ""},
+		{"Point", "class", "com.example.shapes.Point"},
+		{"area", "method", "com.example.shapes.Point.area"},
+		{"Companion", "object", "com.example.shapes.Point.Companion"},
+		{"origin", "method", "com.example.shapes.Point.Companion.origin"},
+		{"Registry", "object", "com.example.shapes.Registry"},
+		{"Shape", "interface", "com.example.shapes.Shape"},
+		{"area", "method", "com.example.shapes.Shape.area"},
+		{"Color", "enum", "com.example.shapes.Color"},
+		{"norm", "method", "com.example.shapes.Point.norm"},
+	}
+
+	if len(definitions) != len(expected) {
+		t.Fatalf("Expected %d definitions, got %d", len(expected), len(definitions))
+	}
+
+	for i, exp := range expected {
+		if definitions[i].Name != exp.name {
+			t.Errorf("Definition %d: expected name %s, got %s", i, exp.name, definitions[i].Name)
+		}
+		if definitions[i].Type != exp.typ {
+			t.Errorf("Definition %d: expected type %s, got %s", i, exp.typ, definitions[i].Type)
+		}
+		if definitions[i].QualifiedName != exp.qualified {
+			t.Errorf("Definition %d: expected qualified name %q, got %q", i, exp.qualified, definitions[i].QualifiedName)
+		}
+	}
+
+	class := definitions[1]
+	if class.Comment != "A point." {
+		t.Errorf("Expected KDoc comment, got %q", class.Comment)
+	}
+	if len(class.Modifiers) != 1 || class.Modifiers[0] != "data" {
+		t.Errorf("Expected data modifier, got %v", class.Modifiers)
+	}
+	if want := "override fun area(scale: Int): Double"; definitions[2].Signature != want {
+		t.Errorf("Expected signature %q, got %q", want, definitions[2].Signature)
+	}
+	if want := "enum class Color { RED, GREEN }"; definitions[8].Definition != want {
+		t.Errorf("Expected enum definition %q, got %q", want, definitions[8].Definition)
+	}
+	if definitions[9].ParentId != class.Id {
+		t.Errorf("Expected extension function to be linked to Point")
+	}
+}
+
+func TestLinkExtensions(t *testing.T) {
+	tempDir := t.TempDir()
+
+	model := filepath.Join(tempDir, "Point.kt")
+	ext := filepath.Join(tempDir, "PointExt.kt")
+	os.WriteFile(model, []byte("package geo\n\nclass Point(val x: Int)\n"), 0644)
+	os.WriteFile(ext, []byte("package geo\n\nfun Point.norm(): Double = 0.0\n"), 0644)
+
+	var files []types.FileMap
+	for _, path := range []string{model, ext} {
+		defs, err := (&KotlinParser{}).Parse(path)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		files = append(files, types.FileMap{Path: path, Language: "kotlin", Definitions: defs})
+	}
+
+	LinkExtensions(files)
+
+	point, norm := files[0].Definitions[1], files[1].Definitions[1]
+	if norm.ParentId != point.Id || norm.Parent != "geo.Point" || norm.QualifiedName != "geo.Point.norm" {
+		t.Errorf("Expected norm to be linked to geo.Point, got parent %q (%q), qualified name %q", norm.Parent, norm.ParentId, norm.QualifiedName)
+	}
+}
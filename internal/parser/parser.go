@@ -1,10 +1,14 @@
 package parser
 
 import (
+	"context"
 	"crypto/md5"
 	"fmt"
+	"os"
 	"strings"
 
+	sitter "github.com/smacker/go-tree-sitter"
+
 	"codemap/internal/types"
 )
 
@@ -12,6 +16,87 @@ import (
 type Parser interface {
 	// Parse analyzes a source file and extracts code definitions
 	Parse(filePath string) ([]types.Definition, error)
+	// ParseContent extracts code definitions from the content of a source
+	// file, which may come from an unsaved editor buffer, a git object or an
+	// archive. filePath names the file in the definitions and ids and is not
+	// read. Parsing stops with ctx's error once ctx is done.
+	ParseContent(ctx context.Context, filePath string, src []byte) ([]types.Definition, error)
+}
+
+// parseFile reads filePath and parses its content with p, which is all the
+// path-based Parse of each parser does
+func parseFile(p Parser, filePath string) ([]types.Definition, error) {
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return p.ParseContent(context.Background(), filePath, src)
+}
+
+// PartialError is returned together with the definitions of a file that has
+// syntax errors. The definitions cover the parts of the file that could be
+// parsed.
+type PartialError struct {
+	Diagnostics []types.Diagnostic
+}
+
+func (e *PartialError) Error() string {
+	d := e.Diagnostics[0]
+	if len(e.Diagnostics) == 1 {
+		return fmt.Sprintf("line %d: %s", d.Line, d.Message)
+	}
+	return fmt.Sprintf("line %d: %s (and %d more)", d.Line, d.Message, len(e.Diagnostics)-1)
+}
+
+// partialResult returns a *PartialError holding diagnostics, or nil when
+// there are none
+func partialResult(diagnostics []types.Diagnostic) error {
+	if len(diagnostics) == 0 {
+		return nil
+	}
+	return &PartialError{Diagnostics: diagnostics}
+}
+
+// syntaxErrors reports the ERROR and MISSING nodes of a tree-sitter tree as
+// a *PartialError, or returns nil when the tree parsed cleanly. Errors
+// nested inside an ERROR node are not reported separately.
+func syntaxErrors(root *sitter.Node, src []byte) error {
+	var diagnostics []types.Diagnostic
+	var walk func(node *sitter.Node)
+	walk = func(node *sitter.Node) {
+		switch {
+		case node.IsMissing():
+			diagnostics = append(diagnostics, types.Diagnostic{
+				Severity: "error",
+				Line:     int(node.StartPoint().Row) + 1,
+				Message:  "missing " + node.Type(),
+			})
+		case node.IsError():
+			message := "syntax error"
+			text, _, _ := strings.Cut(strings.TrimSpace(node.Content(src)), "\n")
+			if r := []rune(text); len(r) > 40 {
+				text = string(r[:40]) + "..."
+			}
+			if text != "" {
+				message += fmt.Sprintf(" at %q", text)
+			}
+			diagnostics = append(diagnostics, types.Diagnostic{
+				Severity: "error",
+				Line:     int(node.StartPoint().Row) + 1,
+				Message:  message,
+			})
+		default:
+			for i := 0; i < int(node.ChildCount()); i++ {
+				if child := node.Child(i); child.HasError() {
+					walk(child)
+				}
+			}
+		}
+	}
+	if root.HasError() {
+		walk(root)
+	}
+	return partialResult(diagnostics)
 }
 
 // computeId generates a stable MD5 hash ID for a definition based on file path, name, and content
@@ -21,17 +106,31 @@ func computeId(filePath, name, content string) string {
 	return fmt.Sprintf("%x", h.Sum(nil))
 }
 
-// GetParser returns the appropriate parser for the given file extension
-func GetParser(filePath string) Parser {
-	// TODO: Implement parser selection based on file extension
-	if strings.HasSuffix(filePath, ".go") {
-		return &GoParser{}
+// linkParent records parent as the enclosing definition of def and
+// qualifies def's name with the parent's
+func linkParent(def, parent *types.Definition) {
+	if parent == nil {
+		return
 	}
-	if strings.HasSuffix(filePath, ".js") || strings.HasSuffix(filePath, ".ts") {
-		return &JSParser{}
+	def.Parent = qualifiedNameOf(*parent)
+	def.ParentId = parent.Id
+	def.QualifiedName = def.Parent + "." + def.Name
+}
+
+// qualifiedNameOf returns the qualified name of def, or its plain name for
+// top-level definitions
+func qualifiedNameOf(def types.Definition) string {
+	if def.QualifiedName != "" {
+		return def.QualifiedName
 	}
-	if strings.HasSuffix(filePath, ".py") {
-		return &PythonParser{}
+	return def.Name
+}
+
+// GetParser returns the parser registered for the file's name, extension
+// or shebang, or nil when the file is not supported
+func GetParser(filePath string) Parser {
+	if lang := Lookup(filePath, nil); lang != nil {
+		return lang.New()
 	}
 	return nil
 }
@@ -0,0 +1,181 @@
+package parser
+
+import (
+	"context"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/php"
+
+	"codemap/internal/types"
+)
+
+// PHPParser implements the Parser interface for PHP files
+type PHPParser struct{}
+
+// Parse reads filePath and extracts its definitions with ParseContent
+func (p *PHPParser) Parse(filePath string) ([]types.Definition, error) {
+	return parseFile(p, filePath)
+}
+
+// ParseContent extracts definitions from PHP source using AST parsing
+func (p *PHPParser) ParseContent(ctx context.Context, filePath string, src []byte) ([]types.Definition, error) {
+	parser := sitter.NewParser()
+	parser.SetLanguage(php.GetLanguage())
+	tree, err := parser.ParseCtx(ctx, nil, src)
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []types.Definition
+
+	walkPHPTree(tree.RootNode(), src, filePath, nil, &definitions)
+
+	return definitions, syntaxErrors(tree.RootNode(), src)
+}
+
+func walkPHPTree(node *sitter.Node, src []byte, filePath string, parent *types.Definition, definitions *[]types.Definition) {
+	var def *types.Definition
+	switch node.Type() {
+	case "namespace_definition":
+		def = extractPHPDeclaration(node, "namespace", src, filePath)
+	case "class_declaration":
+		def = extractPHPDeclaration(node, "class", src, filePath)
+	case "interface_declaration":
+		def = extractPHPDeclaration(node, "interface", src, filePath)
+	case "trait_declaration":
+		def = extractPHPDeclaration(node, "trait", src, filePath)
+	case "enum_declaration":
+		def = extractPHPDeclaration(node, "enum", src, filePath)
+	case "function_definition":
+		def = extractPHPDeclaration(node, "function", src, filePath)
+	case "method_declaration":
+		def = extractPHPDeclaration(node, "method", src, filePath)
+	}
+	if def != nil {
+		linkParent(def, parent)
+		*definitions = append(*definitions, *def)
+		parent = def
+	}
+
+	// Function bodies hold no definitions worth mapping
+	if def != nil && (def.Type == "function" || def.Type == "method") {
+		return
+	}
+
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		walkPHPTree(child, src, filePath, parent, definitions)
+		if child.Type() == "namespace_definition" && child.ChildByFieldName("body") == nil {
+			// "namespace Foo;" encloses every declaration after it
+			if ns := phpLastNamespace(*definitions); ns != nil {
+				parent = ns
+			}
+		}
+	}
+}
+
+// phpLastNamespace returns a copy of the most recently emitted namespace
+func phpLastNamespace(definitions []types.Definition) *types.Definition {
+	for i := len(definitions) - 1; i >= 0; i-- {
+		if definitions[i].Type == "namespace" {
+			ns := definitions[i]
+			return &ns
+		}
+	}
+	return nil
+}
+
+// extractPHPDeclaration builds a definition for a declaration. Types and
+// namespaces are reduced to their header since members are emitted
+// separately; enums keep their cases.
+func extractPHPDeclaration(node *sitter.Node, defType string, src []byte, filePath string) *types.Definition {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil
+	}
+	name := nameNode.Content(src)
+	header := normalizeWhitespace(strings.TrimSuffix(headerText(node, src), ";"))
+
+	def := types.Definition{
+		Type:      defType,
+		Name:      name,
+		Line:      int(node.StartPoint().Row) + 1,
+		LineEnd:   int(node.EndPoint().Row) + 1,
+		Id:        computeId(filePath, name, header),
+		Comment:   extractPHPDoc(node, src),
+		Modifiers: phpModifiers(node, src),
+	}
+
+	switch defType {
+	case "method":
+		// Methods without a visibility modifier are public
+		if !hasPHPVisibility(node) {
+			def.Modifiers = append([]string{"public"}, def.Modifiers...)
+		}
+		def.Signature = header
+	case "function":
+		def.Signature = header
+	case "enum":
+		def.Definition = header + phpEnumCases(node, src)
+	default:
+		def.Definition = header
+	}
+	return &def
+}
+
+// phpModifiers returns the visibility and other modifiers of a declaration
+func phpModifiers(node *sitter.Node, src []byte) []string {
+	var modifiers []string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		switch child.Type() {
+		case "visibility_modifier", "static_modifier", "abstract_modifier", "final_modifier", "readonly_modifier":
+			modifiers = append(modifiers, child.Content(src))
+		}
+	}
+	return modifiers
+}
+
+// hasPHPVisibility reports whether a declaration has a visibility modifier
+func hasPHPVisibility(node *sitter.Node) bool {
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		if node.NamedChild(i).Type() == "visibility_modifier" {
+			return true
+		}
+	}
+	return false
+}
+
+// phpEnumCases renders the cases of an enum body as " { A, B }"
+func phpEnumCases(node *sitter.Node, src []byte) string {
+	body := node.ChildByFieldName("body")
+	if body == nil {
+		return ""
+	}
+	var cases []string
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		if child := body.NamedChild(i); child.Type() == "enum_case" {
+			if name := child.ChildByFieldName("name"); name != nil {
+				cases = append(cases, name.Content(src))
+			}
+		}
+	}
+	if len(cases) == 0 {
+		return ""
+	}
+	return " { " + strings.Join(cases, ", ") + " }"
+}
+
+// extractPHPDoc returns the cleaned PHPDoc block directly above node
+func extractPHPDoc(node *sitter.Node, src []byte) string {
+	prev := node.PrevNamedSibling()
+	if prev == nil || prev.Type() != "comment" {
+		return ""
+	}
+	text := prev.Content(src)
+	if !strings.HasPrefix(text, "/**") {
+		return ""
+	}
+	return cleanDocBlock(text)
+}
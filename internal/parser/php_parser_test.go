@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPHPParser_Parse(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "UserController.php")
+	testContent := `<?php
+namespace App\Admin;
+
+/**
+ * Handles users.
+ */
+final class UserController extends Controller
+{
+    public static function find(int $id): ?User { return null; }
+
+    abstract protected function count(): int;
+}
+
+interface Countable { public function count(): int; }
+trait Loggable { function log($msg) {} }
+enum Suit: string { case Hearts = 'H'; case Spades = 'S'; }
+
+function helper($a) { }
+`
+	err := os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	parser := &PHPParser{}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []struct {
+		name      string
+		typ       string
+		qualified string
+	}{
+		{`App\Admin`, "namespace", ""},
+		{"UserController", "class", `App\Admin.UserController`},
+		{"find", "method", `App\Admin.UserController.find`},
+		{"count", "method", `App\Admin.UserController.count`},
+		{"Countable", "interface", `App\Admin.Countable`},
+		{"count", "method", `App\Admin.Countable.count`},
+		{"Loggable", "trait", `App\Admin.Loggable`},
+		{"log", "method", `App\Admin.Loggable.log`},
+		{"Suit", "enum", `App\Admin.Suit`},
+		{"helper", "function", `App\Admin.helper`},
+	}
+
+	if len(definitions) != len(expected) {
+		t.Fatalf("Expected %d definitions, got %d", len(expected), len(definitions))
+	}
+
+	for i, exp := range expected {
+		if definitions[i].Name != exp.name {
+			t.Errorf("Definition %d: expected name %s, got %s", i, exp.name, definitions[i].Name)
+		}
+		if definitions[i].Type != exp.typ {
+			t.Errorf("Definition %d: expected type %s, got %s", i, exp.typ, definitions[i].Type)
+		}
+		if definitions[i].QualifiedName != exp.qualified {
+			t.Errorf("Definition %d: expected qualified name %q, got %q", i, exp.qualified, definitions[i].QualifiedName)
+		}
+	}
+
+	if definitions[1].Comment != "Handles users." {
+		t.Errorf("Expected PHPDoc comment, got %q", definitions[1].Comment)
+	}
+	if want := "public static function find(int $id): ?User"; definitions[2].Signature != want {
+		t.Errorf("Expected signature %q, got %q", want, definitions[2].Signature)
+	}
+	if mods := definitions[3].Modifiers; len(mods) != 2 || mods[0] != "abstract" || mods[1] != "protected" {
+		t.Errorf("Expected abstract protected modifiers, got %v", mods)
+	}
+	if mods := definitions[7].Modifiers; len(mods) != 1 || mods[0] != "public" {
+		t.Errorf("Expected implicit public visibility, got %v", mods)
+	}
+	if want := "enum Suit: string { Hearts, Spades }"; definitions[8].Definition != want {
+		t.Errorf("Expected enum definition %q, got %q", want, definitions[8].Definition)
+	}
+}
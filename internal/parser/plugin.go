@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"codemap/internal/types"
+)
+
+// defaultPluginTimeout bounds a plugin run when .codemap sets no timeout
+const defaultPluginTimeout = 10 * time.Second
+
+// PluginParser implements the Parser interface by running an external
+// executable. The file is sent on stdin as a JSON object holding its path
+// and content, and the executable answers on stdout with a JSON array of
+// definitions in the same form as the JSON output.
+type PluginParser struct {
+	Command []string      // Executable and its arguments
+	Timeout time.Duration // Time allowed per file
+}
+
+// pluginRequest is the JSON object a plugin reads from stdin
+type pluginRequest struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// RegisterPlugin registers the external parser configured by a .codemap
+// plugins entry for the files matching its pattern
+func RegisterPlugin(plugin types.Plugin) error {
+	if len(plugin.Command) == 0 {
+		return fmt.Errorf("plugin for %s has no command", plugin.Pattern)
+	}
+	timeout := defaultPluginTimeout
+	if plugin.Timeout != "" {
+		d, err := time.ParseDuration(plugin.Timeout)
+		if err != nil {
+			return fmt.Errorf("plugin for %s: invalid timeout: %v", plugin.Pattern, err)
+		}
+		timeout = d
+	}
+	name := plugin.Language
+	if name == "" {
+		name = filepath.Base(plugin.Command[0])
+	}
+	command := plugin.Command
+	return RegisterPattern(plugin.Pattern, Language{
+		Name: name,
+		New:  func() Parser { return &PluginParser{Command: command, Timeout: timeout} },
+	})
+}
+
+// Parse reads filePath and extracts its definitions with ParseContent
+func (p *PluginParser) Parse(filePath string) ([]types.Definition, error) {
+	return parseFile(p, filePath)
+}
+
+// ParseContent runs the plugin on the content of a file and returns the
+// definitions it reports. Definitions without an id are given one. The
+// plugin is killed when ctx is done or its timeout passes.
+func (p *PluginParser) ParseContent(ctx context.Context, filePath string, src []byte) ([]types.Definition, error) {
+	request, err := json.Marshal(pluginRequest{Path: filePath, Content: string(src)})
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = defaultPluginTimeout
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	name := filepath.Base(p.Command[0])
+	cmd := exec.CommandContext(runCtx, p.Command[0], p.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(request)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Don't wait on children that keep the output open after a timeout
+	cmd.WaitDelay = time.Second
+
+	err = cmd.Run()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("plugin %s timed out after %s", name, timeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("plugin %s failed: %v: %s", name, err, msg)
+		}
+		return nil, fmt.Errorf("plugin %s failed: %v", name, err)
+	}
+
+	var definitions []types.Definition
+	if err := json.Unmarshal(stdout.Bytes(), &definitions); err != nil {
+		return nil, fmt.Errorf("plugin %s returned invalid output: %v", name, err)
+	}
+	for i := range definitions {
+		def := &definitions[i]
+		if def.Name == "" || def.Type == "" {
+			return nil, fmt.Errorf("plugin %s returned definition %d without a name or type", name, i)
+		}
+		if def.Id == "" {
+			def.Id = computeId(filePath, qualifiedNameOf(*def), def.Signature+def.Definition)
+		}
+	}
+	return definitions, nil
+}
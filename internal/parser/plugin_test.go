@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"codemap/internal/types"
+)
+
+// writePlugin writes an executable shell script to dir and returns its path
+func writePlugin(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+	return path
+}
+
+func TestPluginParser_Parse(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "rules.dsl")
+	if err := os.WriteFile(testFile, []byte("rule greet\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	// The plugin saves the request so the test can check what was sent
+	requestFile := filepath.Join(tempDir, "request.json")
+	echo := writePlugin(t, tempDir, "echo-plugin", "cat > "+requestFile+"\n"+
+		`echo '[{"type":"rule","name":"greet","line_start":1}]'`+"\n")
+	parser := &PluginParser{Command: []string{echo}}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(definitions) != 1 || definitions[0].Name != "greet" || definitions[0].Type != "rule" {
+		t.Fatalf("Unexpected definitions %+v", definitions)
+	}
+	if definitions[0].Id == "" {
+		t.Errorf("Expected an id to be assigned")
+	}
+	data, err := os.ReadFile(requestFile)
+	if err != nil {
+		t.Fatalf("Plugin received no request: %v", err)
+	}
+	var request pluginRequest
+	if err := json.Unmarshal(data, &request); err != nil || request.Path != testFile || request.Content != "rule greet\n" {
+		t.Errorf("Unexpected request %s", data)
+	}
+
+	failing := writePlugin(t, tempDir, "failing-plugin", "echo 'unknown keyword' >&2\nexit 2\n")
+	if _, err := (&PluginParser{Command: []string{failing}}).Parse(testFile); err == nil || !strings.Contains(err.Error(), "unknown keyword") {
+		t.Errorf("Expected the plugin's error output to be reported, got %v", err)
+	}
+
+	invalid := writePlugin(t, tempDir, "invalid-plugin", "echo '[{\"type\":\"rule\"}]'\n")
+	if _, err := (&PluginParser{Command: []string{invalid}}).Parse(testFile); err == nil {
+		t.Errorf("Expected an error for a definition without a name")
+	}
+
+	slow := writePlugin(t, tempDir, "slow-plugin", "sleep 5\n")
+	start := time.Now()
+	_, err = (&PluginParser{Command: []string{slow}, Timeout: 100 * time.Millisecond}).Parse(testFile)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected a timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Timeout took %s to take effect", elapsed)
+	}
+
+	// Cancelling the caller's context stops the plugin with that error
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = (&PluginParser{Command: []string{slow}}).ParseContent(ctx, testFile, []byte("rule greet\n"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the context's error, got %v", err)
+	}
+}
+
+func TestRegisterPlugin(t *testing.T) {
+	defer func() { byPattern = nil }()
+
+	if err := RegisterPlugin(types.Plugin{Pattern: "rules/**/*.dsl", Command: []string{"/opt/dsl-codemap"}, Timeout: "2s"}); err != nil {
+		t.Fatalf("RegisterPlugin failed: %v", err)
+	}
+	lang := Lookup("rules/auth/login.dsl", nil)
+	if lang == nil || lang.Name != "dsl-codemap" {
+		t.Fatalf("Expected the plugin language, got %+v", lang)
+	}
+	if p, ok := lang.New().(*PluginParser); !ok || p.Timeout != 2*time.Second {
+		t.Errorf("Expected a plugin parser with a 2s timeout, got %+v", lang.New())
+	}
+	if Lookup("other/login.dsl", nil) != nil {
+		t.Errorf("Expected files outside the pattern to stay unsupported")
+	}
+
+	if err := RegisterPlugin(types.Plugin{Pattern: "*.x", Command: []string{"x"}, Timeout: "soon"}); err == nil {
+		t.Errorf("Expected an error for an invalid timeout")
+	}
+}
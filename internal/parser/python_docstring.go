@@ -0,0 +1,310 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"codemap/internal/types"
+)
+
+var (
+	numpyUnderline   = regexp.MustCompile(`^-{3,}$`)
+	googleParamEntry = regexp.MustCompile(`^(\*{0,2}[\w.]+)\s*(?:\(([^)]*)\))?\s*:\s*(.*)$`)
+	numpyParamEntry  = regexp.MustCompile(`^([^:]+?)\s*(?::\s*(.*))?$`)
+	typedEntry       = regexp.MustCompile(`^([\w.]+(?:\[[^\]]*\])?(?:\s*\|\s*[\w.]+(?:\[[^\]]*\])?)*)\s*:\s*(.*)$`)
+	restField        = regexp.MustCompile(`^:(\w+)((?:\s+[^:]+?)?):\s*(.*)$`)
+)
+
+// docEntry is one item of a docstring section: its first line plus any
+// more deeply indented continuation lines
+type docEntry struct {
+	head string
+	desc []string
+}
+
+// pythonDocstring returns the cleaned docstring of a module, class or
+// function body, or "" if the first statement is not a string literal
+func pythonDocstring(body *sitter.Node, src []byte) string {
+	if body == nil {
+		return ""
+	}
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		stmt := body.NamedChild(i)
+		if stmt.Type() == "comment" {
+			continue
+		}
+		if stmt.Type() != "expression_statement" || stmt.NamedChildCount() != 1 {
+			return ""
+		}
+		str := stmt.NamedChild(0)
+		if str.Type() != "string" {
+			return ""
+		}
+		raw := strings.TrimLeft(str.Content(src), "rRuUbB")
+		for _, quote := range []string{`"""`, `'''`, `"`, `'`} {
+			if strings.HasPrefix(raw, quote) && strings.HasSuffix(raw, quote) && len(raw) >= 2*len(quote) {
+				return cleanDocstring(raw[len(quote) : len(raw)-len(quote)])
+			}
+		}
+		return ""
+	}
+	return ""
+}
+
+// cleanDocstring strips a docstring's indentation the way inspect.cleandoc
+// does: the first line is trimmed, the common leading whitespace of the
+// remaining lines is removed, and surrounding blank lines are dropped
+func cleanDocstring(doc string) string {
+	lines := strings.Split(strings.ReplaceAll(doc, "\t", "        "), "\n")
+	margin := -1
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if ind := indentOf(line); margin == -1 || ind < margin {
+			margin = ind
+		}
+	}
+	lines[0] = strings.TrimSpace(lines[0])
+	for i := 1; i < len(lines); i++ {
+		if len(lines[i]) >= margin && margin > 0 {
+			lines[i] = lines[i][margin:]
+		}
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// indentOf returns the number of leading spaces in line
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// docSectionKind maps a docstring section title to "params", "returns" or
+// "raises", or "" for sections that are not parsed
+func docSectionKind(title string) string {
+	switch strings.ToLower(title) {
+	case "args", "arguments", "parameters", "params", "keyword args", "keyword arguments", "other parameters":
+		return "params"
+	case "returns", "return", "yields", "yield":
+		return "returns"
+	case "raises", "raise", "exceptions", "except":
+		return "raises"
+	}
+	return ""
+}
+
+// parsePythonDocstring fills the structured doc fields of def from Google,
+// NumPy or reST style sections in a cleaned docstring
+func parsePythonDocstring(doc string, def *types.Definition) {
+	lines := strings.Split(doc, "\n")
+	lastField := ""
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		indent := indentOf(lines[i])
+
+		// NumPy: a title underlined with dashes
+		if i+1 < len(lines) && numpyUnderline.MatchString(strings.TrimSpace(lines[i+1])) {
+			if kind := docSectionKind(trimmed); kind != "" {
+				entries, next := collectNumpyEntries(lines, i+2, indent)
+				addNumpyEntries(def, kind, entries)
+				i = next - 1
+			}
+			lastField = ""
+			continue
+		}
+
+		// Google: a title ending in a colon with indented entries below
+		if strings.HasSuffix(trimmed, ":") {
+			if kind := docSectionKind(strings.TrimSuffix(trimmed, ":")); kind != "" {
+				entries, next := collectGoogleEntries(lines, i+1, indent)
+				addGoogleEntries(def, kind, entries)
+				i = next - 1
+				lastField = ""
+				continue
+			}
+		}
+
+		// reST: :param name: description, with indented continuation lines
+		if m := restField.FindStringSubmatch(trimmed); m != nil {
+			lastField = addRestField(def, m[1], strings.TrimSpace(m[2]), m[3])
+			continue
+		}
+		if lastField != "" && trimmed != "" && indent > 0 {
+			appendRestDescription(def, lastField, trimmed)
+			continue
+		}
+		lastField = ""
+	}
+}
+
+// collectGoogleEntries gathers the entries of a Google style section that
+// starts at line start; entries are indented deeper than the title
+func collectGoogleEntries(lines []string, start, titleIndent int) ([]docEntry, int) {
+	var entries []docEntry
+	entryIndent := -1
+	j := start
+	for ; j < len(lines); j++ {
+		trimmed := strings.TrimSpace(lines[j])
+		if trimmed == "" {
+			continue
+		}
+		ind := indentOf(lines[j])
+		if ind <= titleIndent {
+			break
+		}
+		if entryIndent == -1 || ind <= entryIndent {
+			entryIndent = ind
+			entries = append(entries, docEntry{head: trimmed})
+		} else {
+			entries[len(entries)-1].desc = append(entries[len(entries)-1].desc, trimmed)
+		}
+	}
+	return entries, j
+}
+
+// collectNumpyEntries gathers the entries of a NumPy style section that
+// starts at line start; entries share the title's indentation and the
+// section ends at the next underlined title
+func collectNumpyEntries(lines []string, start, titleIndent int) ([]docEntry, int) {
+	var entries []docEntry
+	j := start
+	for ; j < len(lines); j++ {
+		trimmed := strings.TrimSpace(lines[j])
+		if trimmed == "" {
+			continue
+		}
+		if j+1 < len(lines) && numpyUnderline.MatchString(strings.TrimSpace(lines[j+1])) {
+			break
+		}
+		ind := indentOf(lines[j])
+		if ind < titleIndent {
+			break
+		}
+		if ind == titleIndent || len(entries) == 0 {
+			entries = append(entries, docEntry{head: trimmed})
+		} else {
+			entries[len(entries)-1].desc = append(entries[len(entries)-1].desc, trimmed)
+		}
+	}
+	return entries, j
+}
+
+func addGoogleEntries(def *types.Definition, kind string, entries []docEntry) {
+	for _, e := range entries {
+		switch kind {
+		case "params":
+			p := types.DocParam{Name: e.head}
+			if m := googleParamEntry.FindStringSubmatch(e.head); m != nil {
+				p = types.DocParam{Name: m[1], Type: m[2], Description: m[3]}
+			}
+			p.Description = joinDescription(p.Description, e.desc)
+			def.Params = append(def.Params, p)
+		case "returns":
+			if def.Returns == nil {
+				def.Returns = &types.DocParam{}
+				if m := typedEntry.FindStringSubmatch(e.head); m != nil {
+					def.Returns.Type = m[1]
+					def.Returns.Description = joinDescription(m[2], e.desc)
+				} else {
+					def.Returns.Description = joinDescription(e.head, e.desc)
+				}
+			} else {
+				def.Returns.Description = joinDescription(def.Returns.Description, append([]string{e.head}, e.desc...))
+			}
+		case "raises":
+			p := types.DocParam{Name: e.head}
+			if m := typedEntry.FindStringSubmatch(e.head); m != nil {
+				p = types.DocParam{Name: m[1], Description: m[2]}
+			}
+			p.Description = joinDescription(p.Description, e.desc)
+			def.Raises = append(def.Raises, p)
+		}
+	}
+}
+
+func addNumpyEntries(def *types.Definition, kind string, entries []docEntry) {
+	for _, e := range entries {
+		switch kind {
+		case "params":
+			p := types.DocParam{Name: e.head}
+			if m := numpyParamEntry.FindStringSubmatch(e.head); m != nil {
+				p = types.DocParam{Name: m[1], Type: m[2]}
+			}
+			p.Description = joinDescription("", e.desc)
+			def.Params = append(def.Params, p)
+		case "returns":
+			if def.Returns != nil {
+				continue
+			}
+			def.Returns = &types.DocParam{Type: e.head, Description: joinDescription("", e.desc)}
+			if m := numpyParamEntry.FindStringSubmatch(e.head); m != nil && m[2] != "" {
+				def.Returns.Name = m[1]
+				def.Returns.Type = m[2]
+			}
+		case "raises":
+			def.Raises = append(def.Raises, types.DocParam{Name: e.head, Description: joinDescription("", e.desc)})
+		}
+	}
+}
+
+// addRestField records a reST field list entry and returns the field kind
+// that continuation lines should be appended to
+func addRestField(def *types.Definition, field, arg, desc string) string {
+	switch field {
+	case "param", "parameter", "arg", "argument", "key", "keyword":
+		p := types.DocParam{Name: arg, Description: desc}
+		if fields := strings.Fields(arg); len(fields) > 1 {
+			p.Type = strings.Join(fields[:len(fields)-1], " ")
+			p.Name = fields[len(fields)-1]
+		}
+		def.Params = append(def.Params, p)
+		return "params"
+	case "type":
+		for i := range def.Params {
+			if def.Params[i].Name == arg {
+				def.Params[i].Type = desc
+				return ""
+			}
+		}
+		def.Params = append(def.Params, types.DocParam{Name: arg, Type: desc})
+		return ""
+	case "returns", "return":
+		if def.Returns == nil {
+			def.Returns = &types.DocParam{}
+		}
+		def.Returns.Description = desc
+		return "returns"
+	case "rtype":
+		if def.Returns == nil {
+			def.Returns = &types.DocParam{}
+		}
+		def.Returns.Type = desc
+		return ""
+	case "raises", "raise", "except", "exception":
+		def.Raises = append(def.Raises, types.DocParam{Name: arg, Description: desc})
+		return "raises"
+	}
+	return ""
+}
+
+func appendRestDescription(def *types.Definition, field, text string) {
+	switch field {
+	case "params":
+		p := &def.Params[len(def.Params)-1]
+		p.Description = joinDescription(p.Description, []string{text})
+	case "returns":
+		def.Returns.Description = joinDescription(def.Returns.Description, []string{text})
+	case "raises":
+		p := &def.Raises[len(def.Raises)-1]
+		p.Description = joinDescription(p.Description, []string{text})
+	}
+}
+
+// joinDescription appends continuation lines to a description
+func joinDescription(first string, rest []string) string {
+	parts := append([]string{first}, rest...)
+	return strings.TrimSpace(strings.Join(parts, " "))
+}
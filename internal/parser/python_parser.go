@@ -1,67 +1,133 @@
 package parser
 
 import (
-	"os"
-	"regexp"
+	"context"
+	"path/filepath"
 	"strings"
 
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/python"
+
 	"codemap/internal/types"
 )
 
 // PythonParser implements the Parser interface for Python files
 type PythonParser struct{}
 
-// Parse extracts definitions from a Python source file using regex patterns
+// Parse reads filePath and extracts its definitions with ParseContent
 func (p *PythonParser) Parse(filePath string) ([]types.Definition, error) {
-	content, err := os.ReadFile(filePath)
+	return parseFile(p, filePath)
+}
+
+// ParseContent extracts definitions from Python source using AST parsing
+func (p *PythonParser) ParseContent(ctx context.Context, filePath string, src []byte) ([]types.Definition, error) {
+	parser := sitter.NewParser()
+	parser.SetLanguage(python.GetLanguage())
+	tree, err := parser.ParseCtx(ctx, nil, src)
 	if err != nil {
 		return nil, err
 	}
 
-	lines := strings.Split(string(content), "\n")
+	lines := strings.Split(string(src), "\n")
 	var definitions []types.Definition
 
-	// Regex patterns for different constructs
-	funcRegex := regexp.MustCompile(`^\s*def\s+(\w+)\s*\(`)
-	classRegex := regexp.MustCompile(`^\s*class\s+(\w+)`)
-	// Note: Python doesn't have interfaces like TypeScript, so just functions and classes
-
-	for i, line := range lines {
-		lineNum := i + 1
-
-		var defType, name string
-		var signature string
-
-		if matches := funcRegex.FindStringSubmatch(line); matches != nil {
-			defType = "function"
-			name = matches[1]
-			signature = strings.TrimSpace(line)
-		} else if matches := classRegex.FindStringSubmatch(line); matches != nil {
-			defType = "type"
-			name = matches[1]
-			signature = strings.TrimSpace(line)
-		}
+	if def := extractPythonModule(tree.RootNode(), src, filePath); def != nil {
+		definitions = append(definitions, *def)
+	}
+	walkPythonTree(tree.RootNode(), src, lines, filePath, nil, &definitions)
+
+	return definitions, syntaxErrors(tree.RootNode(), src)
+}
 
-		if defType != "" {
-			comment := extractPythonComment(lines, i)
-			sig := normalizeWhitespace(signature)
-			def := types.Definition{
-				Type:      defType,
-				Name:      name,
-				Line:       lineNum,
-				LineEnd:    lineNum, // TODO: implement proper end detection
-				Id:        computeId(filePath, name, sig),
-				Signature: sig,
-				Comment:   comment,
-			}
-			definitions = append(definitions, def)
+func walkPythonTree(node *sitter.Node, src []byte, lines []string, filePath string, parent *types.Definition, definitions *[]types.Definition) {
+	var def *types.Definition
+	switch node.Type() {
+	case "function_definition":
+		defType := "function"
+		if parent != nil && parent.Type == "type" {
+			defType = "method"
 		}
+		def = extractPythonDefinition(node, defType, src, lines, filePath)
+	case "class_definition":
+		def = extractPythonDefinition(node, "type", src, lines, filePath)
+	}
+	if def != nil {
+		linkParent(def, parent)
+		*definitions = append(*definitions, *def)
+		parent = def
 	}
 
-	return definitions, nil
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		walkPythonTree(child, src, lines, filePath, parent, definitions)
+	}
 }
 
-// extractPythonComment extracts preceding comment lines (Python uses # for comments)
+// extractPythonDefinition builds a definition for a function or class. The
+// signature runs from the first decorator up to the colon before the body,
+// so multi-line parameter lists keep their annotations and defaults.
+func extractPythonDefinition(node *sitter.Node, defType string, src []byte, lines []string, filePath string) *types.Definition {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil
+	}
+	name := nameNode.Content(src)
+
+	outer := node
+	if parent := node.Parent(); parent != nil && parent.Type() == "decorated_definition" {
+		outer = parent
+	}
+
+	end := node.EndByte()
+	if body := node.ChildByFieldName("body"); body != nil {
+		end = body.StartByte()
+	}
+	sig := normalizeWhitespace(strings.TrimSpace(string(src[outer.StartByte():end])))
+
+	line := int(outer.StartPoint().Row) + 1
+	comment := extractPythonComment(lines, line-1)
+	docstring := pythonDocstring(node.ChildByFieldName("body"), src)
+	if docstring != "" {
+		comment = docstring
+	}
+	def := types.Definition{
+		Type:      defType,
+		Name:      name,
+		Line:      line,
+		LineEnd:   int(outer.EndPoint().Row) + 1,
+		Id:        computeId(filePath, name, sig),
+		Signature: sig,
+		Comment:   comment,
+	}
+	parsePythonDocstring(docstring, &def)
+	return &def
+}
+
+// extractPythonModule builds a module definition when the file opens with a
+// docstring. Packages are named after their directory.
+func extractPythonModule(root *sitter.Node, src []byte, filePath string) *types.Definition {
+	docstring := pythonDocstring(root, src)
+	if docstring == "" {
+		return nil
+	}
+	name := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	if name == "__init__" {
+		name = filepath.Base(filepath.Dir(filePath))
+	}
+	def := types.Definition{
+		Type:    "module",
+		Name:    name,
+		Line:    1,
+		LineEnd: int(root.EndPoint().Row) + 1,
+		Id:      computeId(filePath, name, docstring),
+		Comment: docstring,
+	}
+	parsePythonDocstring(docstring, &def)
+	return &def
+}
+
+// extractPythonComment extracts preceding comment lines (Python uses # for comments).
+// It is the fallback for definitions without a docstring.
 func extractPythonComment(lines []string, currentIndex int) string {
 	var comments []string
 	for i := currentIndex - 1; i >= 0; i-- {
@@ -75,4 +141,4 @@ func extractPythonComment(lines []string, currentIndex int) string {
 		}
 	}
 	return strings.Join(comments, "\n")
-}
\ No newline at end of file
+}
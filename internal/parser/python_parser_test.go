@@ -38,12 +38,13 @@ class Calculator:
 
 	// Check results
 	expected := []struct {
-		name string
-		typ  string
+		name    string
+		typ     string
+		comment string
 	}{
-		{"greet", "function"},
-		{"Calculator", "type"},
-		{"add", "function"},
+		{"greet", "function", "Greet a person."},
+		{"Calculator", "type", "A calculator class."},
+		{"add", "method", ""},
 	}
 
 	if len(definitions) != len(expected) {
@@ -57,6 +58,147 @@ class Calculator:
 		if definitions[i].Type != exp.typ {
 			t.Errorf("Definition %d: expected type %s, got %s", i, exp.typ, definitions[i].Type)
 		}
+		if definitions[i].Comment != exp.comment {
+			t.Errorf("Definition %d: expected comment %q, got %q", i, exp.comment, definitions[i].Comment)
+		}
+	}
+
+	method := definitions[2]
+	if method.Parent != "Calculator" || method.ParentId != definitions[1].Id || method.QualifiedName != "Calculator.add" {
+		t.Errorf("Expected add to be linked to Calculator, got parent %q (%q), qualified name %q", method.Parent, method.ParentId, method.QualifiedName)
+	}
+	if definitions[0].Parent != "" {
+		t.Errorf("Expected greet to have no parent, got %q", definitions[0].Parent)
+	}
+}
+
+func TestPythonParser_DocstringSections(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "test.py")
+	testContent := `"""Geometry helpers."""
+
+def google(x, y=1):
+    """Scale a value.
+
+    Args:
+        x (int): The value.
+        y: The factor, applied
+            after rounding.
+
+    Returns:
+        int: The scaled value.
+
+    Raises:
+        ValueError: If x is negative.
+    """
+
+def numpy(x):
+    """Scale a value.
+
+    Parameters
+    ----------
+    x : int
+        The value.
+
+    Returns
+    -------
+    int
+        The scaled value.
+    """
+
+def rest(x):
+    """Scale a value.
+
+    :param int x: The value.
+    :returns: The scaled value.
+    :rtype: int
+    :raises ValueError: If x is negative.
+    """
+`
+	err := os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	parser := &PythonParser{}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(definitions) != 4 {
+		t.Fatalf("Expected 4 definitions, got %d", len(definitions))
+	}
+
+	module := definitions[0]
+	if module.Type != "module" || module.Name != "test" || module.Comment != "Geometry helpers." {
+		t.Errorf("Unexpected module definition: %+v", module)
+	}
+
+	google := definitions[1]
+	if len(google.Params) != 2 {
+		t.Fatalf("google: expected 2 params, got %+v", google.Params)
+	}
+	if p := google.Params[0]; p.Name != "x" || p.Type != "int" || p.Description != "The value." {
+		t.Errorf("google: unexpected first param %+v", p)
+	}
+	if p := google.Params[1]; p.Name != "y" || p.Description != "The factor, applied after rounding." {
+		t.Errorf("google: unexpected second param %+v", p)
+	}
+	if r := google.Returns; r == nil || r.Type != "int" || r.Description != "The scaled value." {
+		t.Errorf("google: unexpected returns %+v", r)
+	}
+	if len(google.Raises) != 1 || google.Raises[0].Name != "ValueError" {
+		t.Errorf("google: unexpected raises %+v", google.Raises)
+	}
+
+	for _, def := range definitions[2:] {
+		if len(def.Params) != 1 || def.Params[0].Name != "x" || def.Params[0].Type != "int" || def.Params[0].Description != "The value." {
+			t.Errorf("%s: unexpected params %+v", def.Name, def.Params)
+		}
+		if r := def.Returns; r == nil || r.Type != "int" || r.Description != "The scaled value." {
+			t.Errorf("%s: unexpected returns %+v", def.Name, r)
+		}
+	}
+	if len(definitions[3].Raises) != 1 || definitions[3].Raises[0].Name != "ValueError" {
+		t.Errorf("rest: unexpected raises %+v", definitions[3].Raises)
+	}
+}
+
+func TestPythonParser_Signatures(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "test.py")
+	testContent := `@app.route("/items",
+           methods=["GET"])
+async def list_items(request: Request,
+                     limit: int = 10) -> list[Item]:
+    source = "def fake(): pass"
+    return []
+`
+	err := os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	parser := &PythonParser{}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(definitions) != 1 {
+		t.Fatalf("Expected 1 definition, got %d", len(definitions))
+	}
+
+	def := definitions[0]
+	expectedSig := `@app.route("/items", methods=["GET"]) async def list_items(request: Request, limit: int = 10) -> list[Item]:`
+	if def.Signature != expectedSig {
+		t.Errorf("Expected signature %q, got %q", expectedSig, def.Signature)
+	}
+	if def.Line != 1 || def.LineEnd != 6 {
+		t.Errorf("Expected lines 1-6, got %d-%d", def.Line, def.LineEnd)
 	}
 }
 
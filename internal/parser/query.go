@@ -0,0 +1,286 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"codemap/internal/types"
+)
+
+// QueryParser implements the Parser interface by adding the definitions
+// matched by a tree-sitter tags query to those of a built-in parser. The
+// query follows the capture conventions of tags.scm files:
+//
+//	@definition.<kind>  the node of a definition of type <kind>
+//	@name               the node holding its name
+//	@doc                comments documenting it
+//
+// Doc captures may be cleaned with (#strip! @doc "regex"), which removes
+// every match of the regex from each line, and limited with
+// (#select-adjacent! @doc @definition.<kind>) to the comments directly
+// above the definition.
+type QueryParser struct {
+	Base    Parser           // Parser whose definitions the query adds to
+	Grammar *sitter.Language // Grammar the query was compiled against
+	tags    *tagsQuery
+}
+
+// tagsQuery is a compiled tags query and the directives of its patterns
+type tagsQuery struct {
+	query    *sitter.Query
+	patterns []tagsDirectives
+}
+
+// tagsDirectives are the #strip! and #select-adjacent! directives of a
+// query pattern
+type tagsDirectives struct {
+	strip    *regexp.Regexp // Removed from each doc line
+	adjacent string         // Capture the doc comments must sit directly above, "" to keep them all
+}
+
+// LoadQueries reads the tags queries in dir, one <language>.scm file per
+// language named as in FileMap.Language, and applies each to the files of
+// its language, as configured by the queries setting of .codemap
+func LoadQueries(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.scm"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".scm")
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, l := range languages {
+			if l.Name != name {
+				continue
+			}
+			found = true
+			if l.Grammar == nil {
+				return fmt.Errorf("%s: queries are not supported for %s files", path, name)
+			}
+			grammar := l.Grammar()
+			tags, err := compileTagsQuery(src, grammar)
+			if err != nil {
+				return fmt.Errorf("%s: %v", path, err)
+			}
+			base := l.New
+			l.New = func() Parser { return &QueryParser{Base: base(), Grammar: grammar, tags: tags} }
+		}
+		if !found {
+			return fmt.Errorf("%s: unknown language %q", path, name)
+		}
+	}
+	return nil
+}
+
+// compileTagsQuery compiles a tags query and checks the regexes of its
+// predicates, which would otherwise only fail when a file is parsed
+func compileTagsQuery(src []byte, grammar *sitter.Language) (*tagsQuery, error) {
+	q, err := sitter.NewQuery(src, grammar)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %v", err)
+	}
+	tags := &tagsQuery{query: q}
+	for i := uint32(0); i < q.PatternCount(); i++ {
+		var directives tagsDirectives
+		for _, steps := range q.PredicatesForPattern(i) {
+			operator := q.StringValueForId(steps[0].ValueId)
+			switch operator {
+			case "match?", "not-match?", "strip!":
+				if len(steps) < 3 || steps[2].Type != sitter.QueryPredicateStepTypeString {
+					return nil, fmt.Errorf("#%s needs a capture and a regex", operator)
+				}
+				re, err := regexp.Compile("(?m)" + q.StringValueForId(steps[2].ValueId))
+				if err != nil {
+					return nil, fmt.Errorf("#%s: %v", operator, err)
+				}
+				if operator == "strip!" {
+					directives.strip = re
+				}
+			case "select-adjacent!":
+				if len(steps) < 3 || steps[2].Type != sitter.QueryPredicateStepTypeCapture {
+					return nil, fmt.Errorf("#select-adjacent! needs two captures")
+				}
+				directives.adjacent = q.CaptureNameForId(steps[2].ValueId)
+			}
+		}
+		tags.patterns = append(tags.patterns, directives)
+	}
+	return tags, nil
+}
+
+// Parse reads filePath and extracts its definitions with ParseContent
+func (p *QueryParser) Parse(filePath string) ([]types.Definition, error) {
+	return parseFile(p, filePath)
+}
+
+// ParseContent returns the definitions of the base parser followed by those
+// the query matches that the base parser did not already report. Query
+// matches are nested under the innermost definition enclosing them.
+func (p *QueryParser) ParseContent(ctx context.Context, filePath string, src []byte) ([]types.Definition, error) {
+	// Queries still apply to the parts of a file with syntax errors
+	definitions, baseErr := p.Base.ParseContent(ctx, filePath, src)
+	var partial *PartialError
+	if baseErr != nil && !errors.As(baseErr, &partial) {
+		return nil, baseErr
+	}
+
+	parser := sitter.NewParser()
+	parser.SetLanguage(p.Grammar)
+	tree, err := parser.ParseCtx(ctx, nil, src)
+	if err != nil {
+		return nil, err
+	}
+
+	qc := sitter.NewQueryCursor()
+	defer qc.Close()
+	qc.Exec(p.tags.query, tree.RootNode())
+
+	// A pattern with a repeated @doc capture can match one definition
+	// several times with fewer comments; keep the match with the most
+	seen := make(map[string]int)
+	var matched []types.Definition
+	for {
+		m, ok := qc.NextMatch()
+		if !ok {
+			break
+		}
+		m = qc.FilterPredicates(m, src)
+		def := p.tags.definition(m, src, filePath)
+		if def == nil {
+			continue
+		}
+		key := fmt.Sprintf("%s:%d:%d:%s", def.Type, def.Line, def.LineEnd, def.Name)
+		if i, ok := seen[key]; ok {
+			if len(def.Comment) > len(matched[i].Comment) {
+				matched[i].Comment = def.Comment
+			}
+			continue
+		}
+		seen[key] = len(matched)
+		matched = append(matched, *def)
+	}
+
+	base := len(definitions)
+	for _, def := range matched {
+		if reportedAt(definitions[:base], def.Name, def.Line) {
+			continue
+		}
+		// Link to the innermost enclosing definition, base ones included
+		var parent *types.Definition
+		for i := range definitions {
+			d := &definitions[i]
+			if d.Line <= def.Line && d.LineEnd >= def.LineEnd && !(d.Line == def.Line && d.LineEnd == def.LineEnd) {
+				if parent == nil || d.LineEnd-d.Line <= parent.LineEnd-parent.Line {
+					parent = d
+				}
+			}
+		}
+		linkParent(&def, parent)
+		definitions = append(definitions, def)
+	}
+	return definitions, baseErr
+}
+
+// definition builds the definition a query match describes, or returns nil
+// when the match has no @definition capture
+func (t *tagsQuery) definition(m *sitter.QueryMatch, src []byte, filePath string) *types.Definition {
+	var node, nameNode *sitter.Node
+	var kind string
+	var docs []*sitter.Node
+	captured := make(map[string]*sitter.Node)
+	for _, c := range m.Captures {
+		name := t.query.CaptureNameForId(c.Index)
+		captured[name] = c.Node
+		switch {
+		case strings.HasPrefix(name, "definition.") && node == nil:
+			node = c.Node
+			kind = strings.TrimPrefix(name, "definition.")
+		case name == "name" && nameNode == nil:
+			nameNode = c.Node
+		case name == "doc":
+			docs = append(docs, c.Node)
+		}
+	}
+	if node == nil {
+		return nil
+	}
+	if nameNode == nil {
+		nameNode = node.ChildByFieldName("name")
+	}
+	if nameNode == nil {
+		return nil
+	}
+	name := strings.Trim(nameNode.Content(src), "\"'`")
+
+	text := headerText(node, src)
+	if node.ChildByFieldName("body") == nil {
+		text, _, _ = strings.Cut(text, "\n")
+	}
+	text = normalizeWhitespace(text)
+
+	def := types.Definition{
+		Type:    kind,
+		Name:    name,
+		Line:    int(node.StartPoint().Row) + 1,
+		LineEnd: int(node.EndPoint().Row) + 1,
+		Id:      computeId(filePath, name, text),
+	}
+	if kind == "function" || kind == "method" {
+		def.Signature = text
+	} else {
+		def.Definition = text
+	}
+
+	directives := t.patterns[m.PatternIndex]
+	if anchor := captured[directives.adjacent]; anchor != nil {
+		docs = adjacentDocs(docs, anchor)
+	}
+	var lines []string
+	for _, doc := range docs {
+		text := doc.Content(src)
+		if directives.strip != nil {
+			text = directives.strip.ReplaceAllString(text, "")
+		}
+		lines = append(lines, strings.TrimSpace(text))
+	}
+	def.Comment = strings.TrimSpace(strings.Join(lines, "\n"))
+	return &def
+}
+
+// adjacentDocs returns the run of doc nodes ending on the line directly
+// above anchor, with no blank line or code between them
+func adjacentDocs(docs []*sitter.Node, anchor *sitter.Node) []*sitter.Node {
+	sort.Slice(docs, func(i, j int) bool { return docs[i].StartByte() < docs[j].StartByte() })
+	row := anchor.StartPoint().Row
+	start := len(docs)
+	for i := len(docs) - 1; i >= 0; i-- {
+		if docs[i].EndByte() > anchor.StartByte() || docs[i].EndPoint().Row+1 != row {
+			break
+		}
+		row = docs[i].StartPoint().Row
+		start = i
+	}
+	return docs[start:]
+}
+
+// reportedAt reports whether a definition named name starts on line
+func reportedAt(definitions []types.Definition, name string, line int) bool {
+	for _, d := range definitions {
+		if d.Name == name && d.Line == line {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/smacker/go-tree-sitter/javascript"
+)
+
+const expressRoutesQuery = `
+(
+  (comment)* @doc
+  .
+  (expression_statement
+    (call_expression
+      function: (member_expression
+        property: (property_identifier) @method)
+      arguments: (arguments . (string) @name)) @definition.route)
+  (#match? @method "^(get|post|put|delete)$")
+  (#strip! @doc "^//\\s?")
+  (#select-adjacent! @doc @definition.route)
+)
+`
+
+func TestQueryParser_Parse(t *testing.T) {
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "app.js")
+	content := `const app = express();
+
+// Stale comment
+
+// Lists users.
+// Supports paging.
+app.get('/users', (req, res) => {
+  res.json([]);
+});
+
+function setup(router) {
+  router.post("/users", createUser);
+  router.use(logger);
+}
+`
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	tags, err := compileTagsQuery([]byte(expressRoutesQuery), javascript.GetLanguage())
+	if err != nil {
+		t.Fatalf("Failed to compile query: %v", err)
+	}
+	p := &QueryParser{Base: &JSParser{}, Grammar: javascript.GetLanguage(), tags: tags}
+	definitions, err := p.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []struct {
+		Type       string
+		Name       string
+		Line       int
+		Definition string
+		Comment    string
+		Parent     string
+	}{
+		{"function", "setup", 11, "", "", ""},
+		{"route", "/users", 7, "app.get('/users', (req, res) => {", "Lists users.\nSupports paging.", ""},
+		{"route", "/users", 12, `router.post("/users", createUser)`, "", "setup"},
+	}
+	if len(definitions) != len(expected) {
+		t.Fatalf("Expected %d definitions, got %d: %+v", len(expected), len(definitions), definitions)
+	}
+	for i, exp := range expected {
+		def := definitions[i]
+		if def.Type != exp.Type || def.Name != exp.Name || def.Line != exp.Line {
+			t.Errorf("Definition %d: expected %s %s at line %d, got %s %s at line %d", i, exp.Type, exp.Name, exp.Line, def.Type, def.Name, def.Line)
+		}
+		if exp.Definition != "" && def.Definition != exp.Definition {
+			t.Errorf("Definition %d: expected definition %q, got %q", i, exp.Definition, def.Definition)
+		}
+		if def.Comment != exp.Comment {
+			t.Errorf("Definition %d: expected comment %q, got %q", i, exp.Comment, def.Comment)
+		}
+		if def.Parent != exp.Parent {
+			t.Errorf("Definition %d: expected parent %q, got %q", i, exp.Parent, def.Parent)
+		}
+	}
+}
+
+func TestLoadQueries_Errors(t *testing.T) {
+	tests := []struct {
+		file    string
+		query   string
+		message string
+	}{
+		{"cobol.scm", "(comment) @doc", "unknown language"},
+		{"vue.scm", "(comment) @doc", "not supported"},
+		{"javascript.scm", "(no_such_node) @definition.x", "invalid query"},
+		{"javascript.scm", `((identifier) @name (#match? @name "("))`, "#match?"},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, tt.file), []byte(tt.query), 0644); err != nil {
+			t.Fatalf("Failed to create query file: %v", err)
+		}
+		err := LoadQueries(dir)
+		if err == nil || !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("%s %q: expected error containing %q, got %v", tt.file, tt.query, tt.message, err)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/c"
+	"github.com/smacker/go-tree-sitter/cpp"
+	"github.com/smacker/go-tree-sitter/csharp"
+	"github.com/smacker/go-tree-sitter/golang"
+	"github.com/smacker/go-tree-sitter/java"
+	"github.com/smacker/go-tree-sitter/javascript"
+	"github.com/smacker/go-tree-sitter/kotlin"
+	"github.com/smacker/go-tree-sitter/php"
+	"github.com/smacker/go-tree-sitter/python"
+	"github.com/smacker/go-tree-sitter/ruby"
+	"github.com/smacker/go-tree-sitter/rust"
+	"github.com/smacker/go-tree-sitter/swift"
+	"github.com/smacker/go-tree-sitter/typescript/tsx"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+)
+
+// Language describes a parser and the files it handles. Several entries
+// may share a name when one language is parsed in different modes, as
+// TypeScript and TSX are.
+type Language struct {
+	Name       string        // Language recorded in FileMap.Language, e.g. "python"
+	Extensions []string      // File extensions including the dot, e.g. ".py"
+	Filenames  []string      // Base names of files without a telling extension, e.g. "Rakefile"
+	Shebangs   []string      // Interpreters named on a #! line, e.g. "python3"
+	New        func() Parser // Creates a parser for a file
+	// Grammar returns the tree-sitter grammar files are parsed with, which
+	// tags queries are compiled against. It is nil for languages that are
+	// not parsed by a single grammar.
+	Grammar func() *sitter.Language
+}
+
+var (
+	languages   []*Language
+	byExtension = make(map[string]*Language)
+	byFilename  = make(map[string]*Language)
+	byShebang   = make(map[string]*Language)
+	byName      = make(map[string]*Language)
+	byPattern   []patternLanguage
+)
+
+// patternLanguage is a language registered for files matching a glob
+type patternLanguage struct {
+	pattern string
+	lang    *Language
+}
+
+func init() {
+	Register(Language{Name: "go", Extensions: []string{".go"}, New: func() Parser { return &GoParser{} }, Grammar: golang.GetLanguage})
+	Register(Language{Name: "javascript", Extensions: []string{".js", ".jsx"}, Shebangs: []string{"node", "nodejs"}, New: func() Parser { return &JSParser{} }, Grammar: javascript.GetLanguage})
+	Register(Language{Name: "vue", Extensions: []string{".vue"}, New: func() Parser { return &SFCParser{} }})
+	Register(Language{Name: "svelte", Extensions: []string{".svelte"}, New: func() Parser { return &SFCParser{Svelte: true} }})
+	Register(Language{Name: "typescript", Extensions: []string{".ts"}, Shebangs: []string{"ts-node", "deno"}, New: func() Parser { return &TSParser{} }, Grammar: typescript.GetLanguage})
+	Register(Language{Name: "typescript", Extensions: []string{".tsx"}, New: func() Parser { return &TSParser{TSX: true} }, Grammar: tsx.GetLanguage})
+	Register(Language{Name: "python", Extensions: []string{".py"}, Shebangs: []string{"python", "python2", "python3"}, New: func() Parser { return &PythonParser{} }, Grammar: python.GetLanguage})
+	Register(Language{Name: "rust", Extensions: []string{".rs"}, New: func() Parser { return &RustParser{} }, Grammar: rust.GetLanguage})
+	Register(Language{Name: "java", Extensions: []string{".java"}, New: func() Parser { return &JavaParser{} }, Grammar: java.GetLanguage})
+	Register(Language{Name: "ruby", Extensions: []string{".rb"}, Filenames: []string{"Rakefile", "Gemfile"}, Shebangs: []string{"ruby"}, New: func() Parser { return &RubyParser{} }, Grammar: ruby.GetLanguage})
+	Register(Language{Name: "php", Extensions: []string{".php"}, Shebangs: []string{"php"}, New: func() Parser { return &PHPParser{} }, Grammar: php.GetLanguage})
+	Register(Language{Name: "kotlin", Extensions: []string{".kt", ".kts"}, Shebangs: []string{"kotlin"}, New: func() Parser { return &KotlinParser{} }, Grammar: kotlin.GetLanguage})
+	Register(Language{Name: "swift", Extensions: []string{".swift"}, Shebangs: []string{"swift"}, New: func() Parser { return &SwiftParser{} }, Grammar: swift.GetLanguage})
+	Register(Language{Name: "csharp", Extensions: []string{".cs"}, New: func() Parser { return &CSharpParser{} }, Grammar: csharp.GetLanguage})
+	Register(Language{Name: "c", Extensions: []string{".c"}, New: func() Parser { return &CParser{} }, Grammar: c.GetLanguage})
+	// Headers are reported as C but use the C++ grammar, which also
+	// accepts most C
+	Register(Language{Name: "c", Extensions: []string{".h"}, New: func() Parser { return &CParser{CPlusPlus: true} }, Grammar: cpp.GetLanguage})
+	Register(Language{Name: "cpp", Extensions: []string{".cc", ".cpp", ".cxx", ".hh", ".hpp", ".hxx"}, New: func() Parser { return &CParser{CPlusPlus: true} }, Grammar: cpp.GetLanguage})
+}
+
+// Register adds a language to the registry. Its extensions, filenames and
+// shebang interpreters replace those of languages registered before it.
+func Register(lang Language) {
+	l := &lang
+	languages = append(languages, l)
+	for _, ext := range l.Extensions {
+		byExtension[strings.ToLower(ext)] = l
+	}
+	for _, name := range l.Filenames {
+		byFilename[name] = l
+	}
+	for _, interpreter := range l.Shebangs {
+		byShebang[interpreter] = l
+	}
+	if _, ok := byName[l.Name]; !ok {
+		byName[l.Name] = l
+	}
+}
+
+// RegisterPattern makes files whose path matches the glob pattern parse as
+// lang, ahead of any name, extension or shebang match. Patterns are tried in
+// the order they are registered.
+func RegisterPattern(pattern string, lang Language) error {
+	if !doublestar.ValidatePattern(pattern) {
+		return fmt.Errorf("invalid pattern %q", pattern)
+	}
+	byPattern = append(byPattern, patternLanguage{pattern: pattern, lang: &lang})
+	return nil
+}
+
+// RemapExtension makes files with extension ext parse as the language
+// named name, as configured by the extensions setting of .codemap. When
+// several entries share the name, the first registered one is used.
+func RemapExtension(ext, name string) error {
+	l, ok := byName[name]
+	if !ok {
+		return fmt.Errorf("unknown language %q for extension %s", name, ext)
+	}
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	byExtension[strings.ToLower(ext)] = l
+	return nil
+}
+
+// Lookup returns the language of filePath, chosen by the registered glob
+// patterns, then its base name, then its extension, then for files without
+// one the interpreter on the #! line of src, or nil when no parser handles
+// the file. src is the content that will be parsed, such as an unsaved
+// buffer; when it is nil the #! line is read from filePath.
+func Lookup(filePath string, src []byte) *Language {
+	for _, p := range byPattern {
+		if matched, _ := doublestar.Match(p.pattern, filepath.ToSlash(filePath)); matched {
+			return p.lang
+		}
+	}
+	if l, ok := byFilename[filepath.Base(filePath)]; ok {
+		return l
+	}
+	if ext := filepath.Ext(filePath); ext != "" {
+		return byExtension[strings.ToLower(ext)]
+	}
+	if interpreter := shebangInterpreter(filePath, src); interpreter != "" {
+		// python3.12 is tried as python3.12, python3 and python
+		candidates := []string{interpreter}
+		if i := strings.Index(interpreter, "."); i > 0 {
+			candidates = append(candidates, interpreter[:i])
+		}
+		candidates = append(candidates, strings.TrimRight(interpreter, "0123456789."))
+		for _, c := range candidates {
+			if l, ok := byShebang[c]; ok {
+				return l
+			}
+		}
+	}
+	return nil
+}
+
+// shebangInterpreter returns the base name of the interpreter on the #!
+// line of src, or of the file at filePath when src is nil, looking through
+// /usr/bin/env and its flags, or "" when the content does not start with one
+func shebangInterpreter(filePath string, src []byte) string {
+	line, _, _ := strings.Cut(string(src), "\n")
+	if src == nil {
+		f, err := os.Open(filePath)
+		if err != nil {
+			return ""
+		}
+		defer f.Close()
+
+		line, err = bufio.NewReader(f).ReadString('\n')
+		if err != nil && line == "" {
+			return ""
+		}
+	}
+	if !strings.HasPrefix(line, "#!") {
+		return ""
+	}
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		interpreter = ""
+		for _, arg := range fields[1:] {
+			// Skip env's flags and variable assignments
+			if strings.HasPrefix(arg, "-") || strings.Contains(arg, "=") {
+				continue
+			}
+			interpreter = filepath.Base(arg)
+			break
+		}
+	}
+	return interpreter
+}
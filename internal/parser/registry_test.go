@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tempDir := t.TempDir()
+
+	scripts := map[string]string{
+		"manage":   "#!/usr/bin/env python3.12\nprint('hi')\n",
+		"serve":    "#!/usr/bin/env -S node --no-warnings\nconsole.log('hi')\n",
+		"build":    "#!/bin/sh\necho hi\n",
+		"Rakefile": "task :default\n",
+	}
+	for name, content := range scripts {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		file     string
+		language string
+	}{
+		{"main.go", "go"},
+		{"App.TSX", "typescript"},
+		{"util.h", "c"},
+		{"util.hpp", "cpp"},
+		{"README.md", ""},
+		{filepath.Join(tempDir, "manage"), "python"},
+		{filepath.Join(tempDir, "serve"), "javascript"},
+		{filepath.Join(tempDir, "build"), ""},
+		{filepath.Join(tempDir, "Rakefile"), "ruby"},
+	}
+	for _, tt := range tests {
+		lang := Lookup(tt.file, nil)
+		got := ""
+		if lang != nil {
+			got = lang.Name
+		}
+		if got != tt.language {
+			t.Errorf("%s: expected language %q, got %q", tt.file, tt.language, got)
+		}
+	}
+
+	if _, ok := GetParser("App.tsx").(*TSParser); !ok || !GetParser("App.tsx").(*TSParser).TSX {
+		t.Errorf("Expected a TSX parser for .tsx files")
+	}
+}
+
+func TestLookup_Content(t *testing.T) {
+	// The buffer has no file on disk, so only its content can route it
+	path := filepath.Join(t.TempDir(), "manage")
+	if lang := Lookup(path, []byte("#!/usr/bin/env python3\nprint('hi')\n")); lang == nil || lang.Name != "python" {
+		t.Errorf("Expected an in-memory python3 script to map to python, got %+v", lang)
+	}
+
+	// The content being parsed wins over what is on disk
+	if err := os.WriteFile(path, []byte("#!/usr/bin/env node\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if lang := Lookup(path, []byte("#!/usr/bin/python3\n")); lang == nil || lang.Name != "python" {
+		t.Errorf("Expected the buffer's shebang to be used, got %+v", lang)
+	}
+	if lang := Lookup(path, []byte("print('hi')\n")); lang != nil {
+		t.Errorf("Expected a buffer without a shebang to have no language, got %+v", lang)
+	}
+}
+
+func TestRemapExtension(t *testing.T) {
+	defer delete(byExtension, ".pyi")
+
+	if Lookup("stubs.pyi", nil) != nil {
+		t.Fatalf("Expected .pyi to be unmapped by default")
+	}
+	if err := RemapExtension("pyi", "python"); err != nil {
+		t.Fatalf("RemapExtension failed: %v", err)
+	}
+	if lang := Lookup("stubs.pyi", nil); lang == nil || lang.Name != "python" {
+		t.Errorf("Expected .pyi to map to python, got %+v", lang)
+	}
+	if err := RemapExtension(".foo", "cobol"); err == nil {
+		t.Errorf("Expected an error for an unknown language")
+	}
+}
@@ -0,0 +1,330 @@
+package parser
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/ruby"
+
+	"codemap/internal/types"
+)
+
+// RubyParser implements the Parser interface for Ruby files
+type RubyParser struct{}
+
+// Parse reads filePath and extracts its definitions with ParseContent
+func (p *RubyParser) Parse(filePath string) ([]types.Definition, error) {
+	return parseFile(p, filePath)
+}
+
+// ParseContent extracts definitions from Ruby source using AST parsing
+func (p *RubyParser) ParseContent(ctx context.Context, filePath string, src []byte) ([]types.Definition, error) {
+	parser := sitter.NewParser()
+	parser.SetLanguage(ruby.GetLanguage())
+	tree, err := parser.ParseCtx(ctx, nil, src)
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []types.Definition
+
+	walkRubyBody(tree.RootNode(), src, filePath, nil, false, &definitions)
+
+	// Link compact names such as Geo::Line to their scope when it is
+	// declared in this file
+	scopeIds := make(map[string]string)
+	for _, def := range definitions {
+		if def.Type == "module" || def.Type == "class" {
+			scopeIds[qualifiedNameOf(def)] = def.Id
+		}
+	}
+	for i := range definitions {
+		if definitions[i].ParentId == "" && definitions[i].Parent != "" {
+			definitions[i].ParentId = scopeIds[definitions[i].Parent]
+		}
+	}
+
+	return definitions, syntaxErrors(tree.RootNode(), src)
+}
+
+// walkRubyBody walks the statements of a file, module or class body,
+// tracking the visibility set by bare private/protected/public calls.
+// Methods in a class << self body are singleton methods.
+func walkRubyBody(node *sitter.Node, src []byte, filePath string, parent *types.Definition, singleton bool, definitions *[]types.Definition) {
+	visibility := ""
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if child.Type() == "identifier" {
+			switch name := child.Content(src); name {
+			case "private", "protected", "public":
+				visibility = name
+			}
+			continue
+		}
+		walkRubyTree(child, src, filePath, parent, singleton, visibility, definitions)
+	}
+}
+
+func walkRubyTree(node *sitter.Node, src []byte, filePath string, parent *types.Definition, singleton bool, visibility string, definitions *[]types.Definition) {
+	switch node.Type() {
+	case "module", "class":
+		def := extractRubyScope(node, node.Type(), src, filePath, parent)
+		if def == nil {
+			return
+		}
+		*definitions = append(*definitions, *def)
+		if body := node.ChildByFieldName("body"); body != nil {
+			walkRubyBody(body, src, filePath, def, false, definitions)
+		}
+	case "singleton_class":
+		if body := node.ChildByFieldName("body"); body != nil {
+			walkRubyBody(body, src, filePath, parent, true, definitions)
+		}
+	case "method", "singleton_method":
+		def := extractRubyMethod(node, src, filePath, parent)
+		if def == nil {
+			return
+		}
+		if singleton || node.Type() == "singleton_method" {
+			def.Modifiers = append(def.Modifiers, "singleton")
+		}
+		if visibility != "" && visibility != "public" {
+			def.Modifiers = append(def.Modifiers, visibility)
+		}
+		*definitions = append(*definitions, *def)
+	case "assignment":
+		if def := extractRubyConstant(node, src, filePath, parent); def != nil {
+			*definitions = append(*definitions, *def)
+		}
+	case "call":
+		method := node.ChildByFieldName("method")
+		if method == nil || node.ChildByFieldName("receiver") != nil {
+			return
+		}
+		switch name := method.Content(src); name {
+		case "attr_accessor", "attr_reader", "attr_writer":
+			*definitions = append(*definitions, extractRubyAttributes(node, name, src, filePath, parent)...)
+		case "private", "protected", "public":
+			// Inline visibility such as "private def secret"
+			if args := node.ChildByFieldName("arguments"); args != nil {
+				for i := 0; i < int(args.NamedChildCount()); i++ {
+					walkRubyTree(args.NamedChild(i), src, filePath, parent, singleton, name, definitions)
+				}
+			}
+		}
+	}
+}
+
+// extractRubyScope builds a definition for a module or class. Names such as
+// Geo::Line are split into their scope and name.
+func extractRubyScope(node *sitter.Node, defType string, src []byte, filePath string, parent *types.Definition) *types.Definition {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil
+	}
+	var scope []string
+	for nameNode.Type() == "scope_resolution" {
+		if s := nameNode.ChildByFieldName("scope"); s != nil {
+			scope = append(scope, strings.Split(s.Content(src), "::")...)
+		}
+		nameNode = nameNode.ChildByFieldName("name")
+		if nameNode == nil {
+			return nil
+		}
+	}
+	name := nameNode.Content(src)
+
+	// Bodyless one-liners such as "class Error < StandardError; end"
+	header := strings.TrimSpace(strings.TrimSuffix(headerText(node, src), "end"))
+	header = normalizeWhitespace(strings.TrimSpace(strings.TrimSuffix(header, ";")))
+
+	def := types.Definition{
+		Type:    defType,
+		Name:    name,
+		Line:    int(node.StartPoint().Row) + 1,
+		LineEnd: int(node.EndPoint().Row) + 1,
+		Comment: extractRubyComment(node, src),
+	}
+	linkParent(&def, parent)
+	if len(scope) > 0 {
+		prefix := def.Parent
+		if prefix != "" {
+			prefix += "."
+		}
+		def.Parent = prefix + strings.Join(scope, ".")
+		def.ParentId = ""
+		def.QualifiedName = def.Parent + "." + name
+	}
+	def.Definition = header
+	def.Id = computeId(filePath, qualifiedNameOf(def), header)
+	parseYardTags(def.Comment, &def)
+	return &def
+}
+
+// extractRubyMethod builds a definition for a method. The signature is the
+// def line up to the parameters, without the body.
+func extractRubyMethod(node *sitter.Node, src []byte, filePath string, parent *types.Definition) *types.Definition {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil
+	}
+	name := nameNode.Content(src)
+
+	end := nameNode.EndByte()
+	if params := node.ChildByFieldName("parameters"); params != nil {
+		end = params.EndByte()
+	}
+	signature := normalizeWhitespace(strings.TrimSpace(string(src[node.StartByte():end])))
+
+	defType := "function"
+	if parent != nil {
+		defType = "method"
+	}
+	def := types.Definition{
+		Type:      defType,
+		Name:      name,
+		Line:      int(node.StartPoint().Row) + 1,
+		LineEnd:   int(node.EndPoint().Row) + 1,
+		Signature: signature,
+		Comment:   extractRubyComment(node, src),
+	}
+	linkParent(&def, parent)
+	def.Id = computeId(filePath, qualifiedNameOf(def), signature)
+	parseYardTags(def.Comment, &def)
+	return &def
+}
+
+// extractRubyConstant builds a definition for a constant assignment such as
+// VERSION = "1.0"
+func extractRubyConstant(node *sitter.Node, src []byte, filePath string, parent *types.Definition) *types.Definition {
+	left := node.ChildByFieldName("left")
+	if left == nil || left.Type() != "constant" {
+		return nil
+	}
+	name := left.Content(src)
+	definition := normalizeWhitespace(strings.TrimSpace(node.Content(src)))
+	def := types.Definition{
+		Type:       "constant",
+		Name:       name,
+		Line:       int(node.StartPoint().Row) + 1,
+		LineEnd:    int(node.EndPoint().Row) + 1,
+		Definition: definition,
+		Comment:    extractRubyComment(node, src),
+	}
+	linkParent(&def, parent)
+	def.Id = computeId(filePath, qualifiedNameOf(def), definition)
+	return &def
+}
+
+// extractRubyAttributes builds an "attribute" definition for each symbol
+// passed to attr_accessor, attr_reader or attr_writer
+func extractRubyAttributes(node *sitter.Node, macro string, src []byte, filePath string, parent *types.Definition) []types.Definition {
+	args := node.ChildByFieldName("arguments")
+	if args == nil {
+		return nil
+	}
+	comment := extractRubyComment(node, src)
+	var attributes []types.Definition
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		arg := args.NamedChild(i)
+		if arg.Type() != "simple_symbol" {
+			continue
+		}
+		name := strings.TrimPrefix(arg.Content(src), ":")
+		signature := macro + " :" + name
+		def := types.Definition{
+			Type:      "attribute",
+			Name:      name,
+			Line:      int(node.StartPoint().Row) + 1,
+			LineEnd:   int(node.EndPoint().Row) + 1,
+			Signature: signature,
+			Comment:   comment,
+		}
+		linkParent(&def, parent)
+		def.Id = computeId(filePath, qualifiedNameOf(def), signature)
+		attributes = append(attributes, def)
+	}
+	return attributes
+}
+
+// extractRubyComment collects the # comment lines, or =begin/=end block,
+// directly above node
+func extractRubyComment(node *sitter.Node, src []byte) string {
+	var lines []string
+	row := node.StartPoint().Row
+	for prev := node.PrevNamedSibling(); prev != nil && prev.Type() == "comment"; prev = prev.PrevNamedSibling() {
+		if prev.EndPoint().Row+1 < row {
+			break
+		}
+		text := prev.Content(src)
+		if strings.HasPrefix(text, "=begin") {
+			text = strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(text, "=begin")), "=end")
+			lines = append([]string{strings.TrimSpace(text)}, lines...)
+		} else {
+			lines = append([]string{strings.TrimPrefix(strings.TrimPrefix(text, "#"), " ")}, lines...)
+		}
+		row = prev.StartPoint().Row
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+var yardTag = regexp.MustCompile(`^@(param|return|raise)\s*(.*)$`)
+var yardTypes = regexp.MustCompile(`^\[([^\]]*)\]\s*`)
+
+// parseYardTags maps the @param, @return and @raise tags of a YARD comment
+// onto the structured fields of def. Indented lines continue a tag's
+// description.
+func parseYardTags(doc string, def *types.Definition) {
+	var last *types.DocParam
+	for _, line := range strings.Split(doc, "\n") {
+		trimmed := strings.TrimSpace(line)
+		m := yardTag.FindStringSubmatch(trimmed)
+		if m == nil {
+			if last != nil && trimmed != "" && indentOf(line) > 0 {
+				last.Description = strings.TrimSpace(last.Description + " " + trimmed)
+				continue
+			}
+			last = nil
+			continue
+		}
+
+		var entry types.DocParam
+		rest := m[2]
+		// Both "@param name [Type]" and "@param [Type] name" are accepted
+		if m[1] == "param" && !strings.HasPrefix(rest, "[") {
+			fields := strings.SplitN(rest, " ", 2)
+			entry.Name = fields[0]
+			rest = ""
+			if len(fields) > 1 {
+				rest = strings.TrimSpace(fields[1])
+			}
+		}
+		if t := yardTypes.FindStringSubmatch(rest); t != nil {
+			entry.Type = t[1]
+			rest = rest[len(t[0]):]
+		}
+		if m[1] == "param" && entry.Name == "" {
+			fields := strings.SplitN(rest, " ", 2)
+			entry.Name = fields[0]
+			rest = ""
+			if len(fields) > 1 {
+				rest = fields[1]
+			}
+		}
+		entry.Description = strings.TrimSpace(rest)
+
+		switch m[1] {
+		case "param":
+			def.Params = append(def.Params, entry)
+			last = &def.Params[len(def.Params)-1]
+		case "return":
+			def.Returns = &entry
+			last = def.Returns
+		case "raise":
+			def.Raises = append(def.Raises, entry)
+			last = &def.Raises[len(def.Raises)-1]
+		}
+	}
+}
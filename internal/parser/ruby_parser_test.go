@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRubyParser_Parse(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "point.rb")
+	testContent := `module Geo
+  VERSION = "1.0"
+
+  # A point in the plane.
+  class Point < Base
+    attr_accessor :x, :y
+
+    # Creates a point
+    # @param x [Integer] the x coordinate
+    # @param [Integer] y the y coordinate
+    # @return [Point]
+    def initialize(x, y = 0)
+      @x = x
+    end
+
+    def self.origin
+      new(0, 0)
+    end
+
+    private
+
+    def secret; end
+  end
+end
+
+class Geo::Line; end
+`
+	err := os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	parser := &RubyParser{}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []struct {
+		name   string
+		typ    string
+		parent string
+	}{
+		{"Geo", "module", ""},
+		{"VERSION", "constant", "Geo"},
+		{"Point", "class", "Geo"},
+		{"x", "attribute", "Geo.Point"},
+		{"y", "attribute", "Geo.Point"},
+		{"initialize", "method", "Geo.Point"},
+		{"origin", "method", "Geo.Point"},
+		{"secret", "method", "Geo.Point"},
+		{"Line", "class", "Geo"},
+	}
+
+	if len(definitions) != len(expected) {
+		t.Fatalf("Expected %d definitions, got %d", len(expected), len(definitions))
+	}
+
+	for i, exp := range expected {
+		if definitions[i].Name != exp.name {
+			t.Errorf("Definition %d: expected name %s, got %s", i, exp.name, definitions[i].Name)
+		}
+		if definitions[i].Type != exp.typ {
+			t.Errorf("Definition %d: expected type %s, got %s", i, exp.typ, definitions[i].Type)
+		}
+		if definitions[i].Parent != exp.parent {
+			t.Errorf("Definition %d: expected parent %q, got %q", i, exp.parent, definitions[i].Parent)
+		}
+	}
+
+	if definitions[2].Comment != "A point in the plane." {
+		t.Errorf("Expected class comment, got %q", definitions[2].Comment)
+	}
+
+	init := definitions[5]
+	if init.Signature != "def initialize(x, y = 0)" {
+		t.Errorf("Unexpected signature %q", init.Signature)
+	}
+	if len(init.Params) != 2 || init.Params[0].Name != "x" || init.Params[1].Name != "y" || init.Params[1].Type != "Integer" {
+		t.Errorf("Unexpected params %+v", init.Params)
+	}
+	if init.Returns == nil || init.Returns.Type != "Point" {
+		t.Errorf("Unexpected returns %+v", init.Returns)
+	}
+	if mods := definitions[6].Modifiers; len(mods) != 1 || mods[0] != "singleton" {
+		t.Errorf("Expected origin to be a singleton method, got %v", mods)
+	}
+	if mods := definitions[7].Modifiers; len(mods) != 1 || mods[0] != "private" {
+		t.Errorf("Expected secret to be private, got %v", mods)
+	}
+	if definitions[8].ParentId != definitions[0].Id {
+		t.Errorf("Expected Geo::Line to be linked to Geo")
+	}
+}
@@ -0,0 +1,258 @@
+package parser
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/rust"
+
+	"codemap/internal/types"
+)
+
+// RustParser implements the Parser interface for Rust files
+type RustParser struct{}
+
+// Parse reads filePath and extracts its definitions with ParseContent
+func (p *RustParser) Parse(filePath string) ([]types.Definition, error) {
+	return parseFile(p, filePath)
+}
+
+// ParseContent extracts definitions from Rust source using AST parsing
+func (p *RustParser) ParseContent(ctx context.Context, filePath string, src []byte) ([]types.Definition, error) {
+	parser := sitter.NewParser()
+	parser.SetLanguage(rust.GetLanguage())
+	tree, err := parser.ParseCtx(ctx, nil, src)
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []types.Definition
+
+	root := tree.RootNode()
+	if doc := rustInnerDoc(root, src); doc != "" {
+		name := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+		switch name {
+		case "mod":
+			name = filepath.Base(filepath.Dir(filePath))
+		case "lib", "main":
+			name = "crate"
+		}
+		definitions = append(definitions, types.Definition{
+			Type:    "module",
+			Name:    name,
+			Line:    1,
+			LineEnd: int(root.EndPoint().Row) + 1,
+			Id:      computeId(filePath, name, doc),
+			Comment: doc,
+		})
+	}
+
+	walkRustTree(root, src, filePath, nil, &definitions)
+
+	// Link impl blocks and their methods to the type they implement when
+	// it is declared in this file
+	typeIds := make(map[string]string)
+	for _, def := range definitions {
+		switch def.Type {
+		case "struct", "enum", "union", "trait", "type_alias":
+			typeIds[qualifiedNameOf(def)] = def.Id
+		}
+	}
+	for i := range definitions {
+		if definitions[i].ParentId == "" && definitions[i].Parent != "" {
+			definitions[i].ParentId = typeIds[definitions[i].Parent]
+		}
+	}
+
+	return definitions, syntaxErrors(tree.RootNode(), src)
+}
+
+func walkRustTree(node *sitter.Node, src []byte, filePath string, parent *types.Definition, definitions *[]types.Definition) {
+	var def *types.Definition
+	switch node.Type() {
+	case "function_item", "function_signature_item":
+		defType := "function"
+		if parent != nil && (parent.Type == "impl" || parent.Type == "trait") {
+			defType = "method"
+		}
+		def = extractRustItem(node, defType, src, filePath)
+	case "struct_item":
+		def = extractRustItem(node, "struct", src, filePath)
+	case "union_item":
+		def = extractRustItem(node, "union", src, filePath)
+	case "enum_item":
+		def = extractRustItem(node, "enum", src, filePath)
+	case "trait_item":
+		def = extractRustItem(node, "trait", src, filePath)
+	case "type_item":
+		def = extractRustItem(node, "type_alias", src, filePath)
+	case "macro_definition":
+		def = extractRustItem(node, "macro", src, filePath)
+	case "mod_item":
+		def = extractRustItem(node, "module", src, filePath)
+	case "impl_item":
+		// Impls are linked to the implemented type rather than the
+		// enclosing scope, and inherent impls are not emitted at all
+		if impl := extractRustImpl(node, src, filePath, parent); impl != nil {
+			if impl.Id != "" {
+				*definitions = append(*definitions, *impl)
+			}
+			parent = impl
+		}
+	}
+	if def != nil {
+		linkParent(def, parent)
+		*definitions = append(*definitions, *def)
+		parent = def
+	}
+
+	walkRustChildren(node, src, filePath, parent, definitions)
+}
+
+func walkRustChildren(node *sitter.Node, src []byte, filePath string, parent *types.Definition, definitions *[]types.Definition) {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		walkRustTree(child, src, filePath, parent, definitions)
+	}
+}
+
+// extractRustItem builds a definition for a named Rust item. Functions carry
+// their signature; traits and modules are reduced to their header since
+// their members are emitted separately.
+func extractRustItem(node *sitter.Node, defType string, src []byte, filePath string) *types.Definition {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil
+	}
+	name := nameNode.Content(src)
+
+	def := types.Definition{
+		Type:    defType,
+		Name:    name,
+		Line:    int(node.StartPoint().Row) + 1,
+		LineEnd: int(node.EndPoint().Row) + 1,
+		Comment: extractRustDoc(node, src),
+	}
+
+	var content string
+	switch defType {
+	case "function", "method":
+		content = normalizeWhitespace(strings.TrimSuffix(headerText(node, src), ";"))
+		def.Signature = content
+	case "macro":
+		content = "macro_rules! " + name
+		def.Definition = content
+	case "trait", "module":
+		content = normalizeWhitespace(strings.TrimSuffix(headerText(node, src), ";"))
+		def.Definition = content
+		if body := node.ChildByFieldName("body"); body != nil && defType == "module" {
+			if inner := rustInnerDoc(body, src); inner != "" {
+				def.Comment = strings.TrimSpace(def.Comment + "\n" + inner)
+			}
+		}
+	default:
+		content = normalizeWhitespace(node.Content(src))
+		def.Definition = content
+	}
+	def.Id = computeId(filePath, name, content)
+	return &def
+}
+
+// extractRustImpl returns the definition for a trait impl. For inherent
+// impls it returns a placeholder without an Id that only carries the
+// implemented type as the parent of the impl's methods.
+func extractRustImpl(node *sitter.Node, src []byte, filePath string, parent *types.Definition) *types.Definition {
+	typeNode := node.ChildByFieldName("type")
+	if typeNode == nil {
+		return nil
+	}
+	typeDef := &types.Definition{Type: "impl", Name: rustTypeName(typeNode, src)}
+	linkParent(typeDef, parent)
+
+	traitNode := node.ChildByFieldName("trait")
+	if traitNode == nil {
+		return typeDef
+	}
+
+	header := normalizeWhitespace(headerText(node, src))
+	def := types.Definition{
+		Type:       "impl",
+		Name:       rustTypeName(traitNode, src),
+		Line:       int(node.StartPoint().Row) + 1,
+		LineEnd:    int(node.EndPoint().Row) + 1,
+		Definition: header,
+		Comment:    extractRustDoc(node, src),
+	}
+	linkParent(&def, typeDef)
+	def.Id = computeId(filePath, def.QualifiedName, header)
+	return &def
+}
+
+// rustTypeName returns the bare name of a type, without path, references or
+// generic arguments
+func rustTypeName(node *sitter.Node, src []byte) string {
+	switch node.Type() {
+	case "generic_type", "reference_type", "pointer_type":
+		if inner := node.ChildByFieldName("type"); inner != nil {
+			return rustTypeName(inner, src)
+		}
+	case "scoped_type_identifier":
+		if name := node.ChildByFieldName("name"); name != nil {
+			return name.Content(src)
+		}
+	}
+	return node.Content(src)
+}
+
+// extractRustDoc collects the outer doc comments (/// or /** */) above an
+// item, skipping over attributes such as #[derive]
+func extractRustDoc(node *sitter.Node, src []byte) string {
+	var docs []string
+	for prev := node.PrevNamedSibling(); prev != nil; prev = prev.PrevNamedSibling() {
+		if prev.Type() == "attribute_item" {
+			continue
+		}
+		if !isRustDocComment(prev, "outer") {
+			break
+		}
+		docs = append([]string{rustDocText(prev, src)}, docs...)
+	}
+	return strings.Join(docs, "\n")
+}
+
+// rustInnerDoc collects the inner doc comments (//!) at the start of a file
+// or module body
+func rustInnerDoc(node *sitter.Node, src []byte) string {
+	var docs []string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if !isRustDocComment(child, "inner") {
+			break
+		}
+		docs = append(docs, rustDocText(child, src))
+	}
+	return strings.Join(docs, "\n")
+}
+
+// isRustDocComment reports whether node is a doc comment with the given
+// marker field ("outer" for ///, "inner" for //!)
+func isRustDocComment(node *sitter.Node, marker string) bool {
+	if node.Type() != "line_comment" && node.Type() != "block_comment" {
+		return false
+	}
+	return node.ChildByFieldName(marker) != nil && node.ChildByFieldName("doc") != nil
+}
+
+// rustDocText returns the text of a doc comment without its markers
+func rustDocText(node *sitter.Node, src []byte) string {
+	text := node.ChildByFieldName("doc").Content(src)
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "* ")
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRustParser_Parse(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "shapes.rs")
+	testContent := `//! Shape utilities
+
+/// A point in the plane
+#[derive(Debug, Clone)]
+pub struct Point { pub x: f64, pub y: f64 }
+
+pub trait Area {
+    fn area(&self) -> f64;
+}
+
+impl Point {
+    /// Creates a point
+    pub fn new(x: f64, y: f64) -> Self {
+        Point { x, y }
+    }
+}
+
+impl fmt::Display for Point {
+    fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+        write!(f, "({}, {})", self.x, self.y)
+    }
+}
+
+macro_rules! square {
+    ($x:expr) => { $x * $x };
+}
+
+mod geo {
+    pub fn distance(a: &Point, b: &Point) -> f64 { 0.0 }
+}
+`
+	err := os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	parser := &RustParser{}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []struct {
+		name   string
+		typ    string
+		parent string
+	}{
+		{"shapes", "module", ""},
+		{"Point", "struct", ""},
+		{"Area", "trait", ""},
+		{"area", "method", "Area"},
+		{"new", "method", "Point"},
+		{"Display", "impl", "Point"},
+		{"fmt", "method", "Point.Display"},
+		{"square", "macro", ""},
+		{"geo", "module", ""},
+		{"distance", "function", "geo"},
+	}
+
+	if len(definitions) != len(expected) {
+		t.Fatalf("Expected %d definitions, got %d", len(expected), len(definitions))
+	}
+
+	for i, exp := range expected {
+		if definitions[i].Name != exp.name {
+			t.Errorf("Definition %d: expected name %s, got %s", i, exp.name, definitions[i].Name)
+		}
+		if definitions[i].Type != exp.typ {
+			t.Errorf("Definition %d: expected type %s, got %s", i, exp.typ, definitions[i].Type)
+		}
+		if definitions[i].Parent != exp.parent {
+			t.Errorf("Definition %d: expected parent %q, got %q", i, exp.parent, definitions[i].Parent)
+		}
+	}
+
+	if definitions[0].Comment != "Shape utilities" {
+		t.Errorf("Expected module doc, got %q", definitions[0].Comment)
+	}
+	if definitions[1].Comment != "A point in the plane" {
+		t.Errorf("Expected struct doc, got %q", definitions[1].Comment)
+	}
+	if definitions[4].ParentId != definitions[1].Id || definitions[5].ParentId != definitions[1].Id {
+		t.Errorf("Expected impl members to be linked to Point")
+	}
+	if sig := definitions[4].Signature; sig != "pub fn new(x: f64, y: f64) -> Self" {
+		t.Errorf("Unexpected signature %q", sig)
+	}
+}
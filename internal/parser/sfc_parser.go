@@ -0,0 +1,334 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"codemap/internal/types"
+)
+
+// SFCParser implements the Parser interface for Vue and Svelte single-file
+// components. Script blocks are parsed by the JS or TS parser with their
+// lines mapped back to the component file, and the component itself is
+// emitted with its props, emits and slots.
+type SFCParser struct {
+	// Svelte selects Svelte conventions for props, events and slots
+	Svelte bool
+}
+
+var (
+	sfcScript     = regexp.MustCompile(`(?s)<script\b([^>]*)>(.*?)</script>`)
+	sfcStyle      = regexp.MustCompile(`(?s)<style\b[^>]*>.*?</style>`)
+	sfcTemplate   = regexp.MustCompile(`(?s)<template\b[^>]*>(.*)</template>`)
+	sfcLang       = regexp.MustCompile(`\blang\s*=\s*["']?(\w+)`)
+	sfcSlot       = regexp.MustCompile(`<slot\b([^>]*)>`)
+	sfcSlotName   = regexp.MustCompile(`\bname\s*=\s*["']([^"']+)["']`)
+	sfcRender     = regexp.MustCompile(`\{@render\s+(\w+)\s*\(`)
+	sfcEmitCall   = regexp.MustCompile(`\$emit\(\s*['"]([^'"]+)['"]`)
+	sfcDocComment = regexp.MustCompile(`(?s)^\s*<!--(.*?)-->`)
+)
+
+// Parse reads filePath and extracts its definitions with ParseContent
+func (p *SFCParser) Parse(filePath string) ([]types.Definition, error) {
+	return parseFile(p, filePath)
+}
+
+// ParseContent extracts the component and its script definitions from the
+// content of a .vue or .svelte file
+func (p *SFCParser) ParseContent(ctx context.Context, filePath string, src []byte) ([]types.Definition, error) {
+	name := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	component := types.Definition{
+		Type:    "component",
+		Name:    name,
+		Line:    1,
+		LineEnd: strings.Count(string(src), "\n") + 1,
+		Export:  "default",
+	}
+	if m := sfcDocComment.FindSubmatch(src); m != nil {
+		component.Comment = strings.TrimSpace(string(m[1]))
+	}
+
+	var tags []string
+	var scripts []types.Definition
+	var diagnostics []types.Diagnostic
+	for _, m := range sfcScript.FindAllSubmatchIndex(src, -1) {
+		attrs := string(src[m[2]:m[3]])
+		script := src[m[4]:m[5]]
+		offset := strings.Count(string(src[:m[4]]), "\n")
+		tags = append(tags, normalizeWhitespace(string(src[m[0]:m[4]])))
+
+		var parser sfcScriptParser = &JSParser{}
+		if lang := sfcLang.FindStringSubmatch(attrs); lang != nil && (lang[1] == "ts" || lang[1] == "tsx") {
+			parser = &TSParser{TSX: lang[1] == "tsx"}
+		}
+		// The tree is mapped for definitions and scanned for props and emits
+		tree, err := parser.parseTree(ctx, script)
+		if err != nil {
+			return nil, err
+		}
+		defs, err := parser.mapTree(tree, script, filePath)
+		var partial *PartialError
+		if errors.As(err, &partial) {
+			for _, d := range partial.Diagnostics {
+				d.Line += offset
+				diagnostics = append(diagnostics, d)
+			}
+		} else if err != nil {
+			return nil, err
+		}
+		for _, def := range defs {
+			def.Line += offset
+			def.LineEnd += offset
+			scripts = append(scripts, def)
+		}
+
+		p.scanScript(tree.RootNode(), script, &component)
+	}
+
+	markup := src
+	if !p.Svelte {
+		markup = nil
+		if m := sfcTemplate.FindSubmatch(src); m != nil {
+			markup = m[1]
+			tags = append([]string{"<template>"}, tags...)
+		}
+	} else {
+		markup = sfcScript.ReplaceAll(markup, nil)
+		markup = sfcStyle.ReplaceAll(markup, nil)
+	}
+	p.scanMarkup(markup, &component)
+
+	component.Definition = strings.Join(tags, " ")
+	component.Id = computeId(filePath, name, component.Definition)
+
+	// Top-level script definitions belong to the component
+	definitions := []types.Definition{component}
+	for _, def := range scripts {
+		if def.ParentId == "" && def.Parent == "" {
+			linkParent(&def, &component)
+		} else {
+			def.Parent = name + "." + def.Parent
+			def.QualifiedName = name + "." + def.QualifiedName
+		}
+		definitions = append(definitions, def)
+	}
+	return definitions, partialResult(diagnostics)
+}
+
+// sfcScriptParser is implemented by the parsers of script blocks, whose
+// tree the SFC parser also scans for props and emits
+type sfcScriptParser interface {
+	parseTree(ctx context.Context, src []byte) (*sitter.Tree, error)
+	mapTree(tree *sitter.Tree, src []byte, filePath string) ([]types.Definition, error)
+}
+
+// scanScript records the props and emits a script block declares: Vue's
+// defineProps/defineEmits macros or props/emits options, and Svelte's
+// exported lets, $props() and dispatched events
+func (p *SFCParser) scanScript(root *sitter.Node, script []byte, component *types.Definition) {
+	var svelteProps []string
+	var walk func(node *sitter.Node)
+	walk = func(node *sitter.Node) {
+		switch node.Type() {
+		case "call_expression":
+			fn := node.ChildByFieldName("function")
+			if fn == nil {
+				break
+			}
+			switch fn.Content(script) {
+			case "defineProps", "withDefaults":
+				if component.Props == "" {
+					component.Props = sfcMacroProps(node, script)
+				}
+			case "defineEmits":
+				appendUnique(&component.Emits, sfcMacroEmits(node, script)...)
+			case "dispatch":
+				if p.Svelte {
+					appendUnique(&component.Emits, sfcStringArgs(node.ChildByFieldName("arguments"), script, 1)...)
+				}
+			case "$props":
+				// Svelte 5: let { a, b }: Props = $props()
+				if declarator := node.Parent(); declarator != nil && declarator.Type() == "variable_declarator" {
+					if t := declarator.ChildByFieldName("type"); t != nil {
+						component.Props = strings.TrimSpace(strings.TrimPrefix(t.Content(script), ":"))
+					} else if n := declarator.ChildByFieldName("name"); n != nil {
+						component.Props = normalizeWhitespace(n.Content(script))
+					}
+				}
+			}
+		case "export_statement":
+			// Svelte 4: export let name. Exported consts and functions are
+			// read-only exports, not props.
+			if decl := node.ChildByFieldName("declaration"); p.Svelte && decl != nil && decl.Type() == "lexical_declaration" && decl.Child(0).Type() == "let" {
+				for i := 0; i < int(decl.NamedChildCount()); i++ {
+					if n := decl.NamedChild(i).ChildByFieldName("name"); n != nil {
+						svelteProps = append(svelteProps, n.Content(script))
+					}
+				}
+			}
+			// Vue options API: export default { props, emits }
+			if value := node.ChildByFieldName("value"); value != nil && !p.Svelte {
+				p.scanOptions(value, script, component)
+			}
+		}
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			walk(node.NamedChild(i))
+		}
+	}
+	walk(root)
+
+	if len(svelteProps) > 0 {
+		component.Props = "{ " + strings.Join(svelteProps, ", ") + " }"
+	}
+}
+
+// scanOptions records the props and emits options of a Vue options API
+// component, given as a plain object or wrapped in defineComponent
+func (p *SFCParser) scanOptions(value *sitter.Node, script []byte, component *types.Definition) {
+	if value.Type() == "call_expression" {
+		args := value.ChildByFieldName("arguments")
+		if args == nil || args.NamedChildCount() == 0 {
+			return
+		}
+		value = args.NamedChild(0)
+	}
+	if value.Type() != "object" {
+		return
+	}
+	for i := 0; i < int(value.NamedChildCount()); i++ {
+		pair := value.NamedChild(i)
+		if pair.Type() != "pair" {
+			continue
+		}
+		key, v := pair.ChildByFieldName("key"), pair.ChildByFieldName("value")
+		if key == nil || v == nil {
+			continue
+		}
+		switch key.Content(script) {
+		case "props":
+			component.Props = normalizeWhitespace(v.Content(script))
+		case "emits":
+			appendUnique(&component.Emits, sfcNames(v, script)...)
+		}
+	}
+}
+
+// sfcMacroProps returns the props declared by defineProps: its type
+// argument, or its runtime declaration
+func sfcMacroProps(call *sitter.Node, script []byte) string {
+	if fn := call.ChildByFieldName("function"); fn != nil && fn.Content(script) == "withDefaults" {
+		// withDefaults(defineProps<Props>(), {...})
+		args := call.ChildByFieldName("arguments")
+		if args == nil || args.NamedChildCount() == 0 {
+			return ""
+		}
+		call = args.NamedChild(0)
+	}
+	if typeArgs := call.ChildByFieldName("type_arguments"); typeArgs != nil && typeArgs.NamedChildCount() > 0 {
+		return normalizeWhitespace(typeArgs.NamedChild(0).Content(script))
+	}
+	if args := call.ChildByFieldName("arguments"); args != nil && args.NamedChildCount() > 0 {
+		return normalizeWhitespace(args.NamedChild(0).Content(script))
+	}
+	return ""
+}
+
+// sfcMacroEmits returns the event names declared by defineEmits, from
+// either its type argument or its runtime declaration
+func sfcMacroEmits(call *sitter.Node, script []byte) []string {
+	if typeArgs := call.ChildByFieldName("type_arguments"); typeArgs != nil && typeArgs.NamedChildCount() > 0 {
+		return sfcNames(typeArgs.NamedChild(0), script)
+	}
+	if args := call.ChildByFieldName("arguments"); args != nil && args.NamedChildCount() > 0 {
+		return sfcNames(args.NamedChild(0), script)
+	}
+	return nil
+}
+
+// sfcNames returns the names listed by an array of strings, the keys of an
+// object, or the events of an emits type literal: property names and the
+// string literal first parameter of call signatures
+func sfcNames(node *sitter.Node, script []byte) []string {
+	var names []string
+	switch node.Type() {
+	case "array":
+		names = sfcStringArgs(node, script, -1)
+	case "object", "object_type":
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			member := node.NamedChild(i)
+			switch member.Type() {
+			case "pair", "property_signature", "method_definition", "shorthand_property_identifier":
+				key := member.ChildByFieldName("key")
+				if key == nil {
+					key = member.ChildByFieldName("name")
+				}
+				if key == nil {
+					key = member
+				}
+				names = append(names, strings.Trim(key.Content(script), `"'`))
+			case "call_signature":
+				params := member.ChildByFieldName("parameters")
+				if params == nil || params.NamedChildCount() == 0 {
+					continue
+				}
+				if t := params.NamedChild(0).ChildByFieldName("type"); t != nil {
+					names = append(names, strings.Trim(strings.TrimSpace(strings.TrimPrefix(t.Content(script), ":")), `"'`))
+				}
+			}
+		}
+	}
+	return names
+}
+
+// sfcStringArgs returns the string literals among the first limit named
+// children of node, or all of them when limit is negative
+func sfcStringArgs(node *sitter.Node, script []byte, limit int) []string {
+	if node == nil {
+		return nil
+	}
+	var values []string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		if limit >= 0 && i >= limit {
+			break
+		}
+		if child := node.NamedChild(i); child.Type() == "string" {
+			values = append(values, strings.Trim(child.Content(script), `"'`+"`"))
+		}
+	}
+	return values
+}
+
+// scanMarkup records the slots a component's markup declares, and for Vue
+// the events emitted inline with $emit
+func (p *SFCParser) scanMarkup(markup []byte, component *types.Definition) {
+	for _, m := range sfcSlot.FindAllSubmatch(markup, -1) {
+		name := "default"
+		if n := sfcSlotName.FindSubmatch(m[1]); n != nil {
+			name = string(n[1])
+		}
+		appendUnique(&component.Slots, name)
+	}
+	if p.Svelte {
+		for _, m := range sfcRender.FindAllSubmatch(markup, -1) {
+			appendUnique(&component.Slots, string(m[1]))
+		}
+		return
+	}
+	for _, m := range sfcEmitCall.FindAllSubmatch(markup, -1) {
+		appendUnique(&component.Emits, string(m[1]))
+	}
+}
+
+// appendUnique appends the values not already in list
+func appendUnique(list *[]string, values ...string) {
+	for _, v := range values {
+		if !containsString(*list, v) {
+			*list = append(*list, v)
+		}
+	}
+}
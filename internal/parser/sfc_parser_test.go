@@ -0,0 +1,138 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSFCParser_ParseVue(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "UserCard.vue")
+	testContent := `<!-- A user card -->
+<template>
+  <div>
+    <slot name="header" />
+    <slot></slot>
+    <button @click="$emit('close')">x</button>
+  </div>
+</template>
+
+<script setup lang="ts">
+const props = defineProps<{ title: string }>()
+const emit = defineEmits<{ (e: 'change', id: number): void }>()
+
+// Handles clicks
+function onClick() {}
+</script>
+`
+	err := os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	parser := &SFCParser{}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(definitions) != 2 {
+		t.Fatalf("Expected 2 definitions, got %d", len(definitions))
+	}
+
+	component := definitions[0]
+	if component.Type != "component" || component.Name != "UserCard" {
+		t.Errorf("Expected UserCard component, got %s %s", component.Type, component.Name)
+	}
+	if component.Comment != "A user card" {
+		t.Errorf("Expected component comment, got %q", component.Comment)
+	}
+	if component.Props != "{ title: string }" {
+		t.Errorf("Unexpected props %q", component.Props)
+	}
+	if len(component.Emits) != 2 || component.Emits[0] != "change" || component.Emits[1] != "close" {
+		t.Errorf("Unexpected emits %v", component.Emits)
+	}
+	if len(component.Slots) != 2 || component.Slots[0] != "header" || component.Slots[1] != "default" {
+		t.Errorf("Unexpected slots %v", component.Slots)
+	}
+
+	fn := definitions[1]
+	if fn.Name != "onClick" || fn.Line != 15 {
+		t.Errorf("Expected onClick on line 15, got %s on line %d", fn.Name, fn.Line)
+	}
+	if fn.Comment != " Handles clicks" {
+		t.Errorf("Expected script comment, got %q", fn.Comment)
+	}
+	if fn.ParentId != component.Id || fn.QualifiedName != "UserCard.onClick" {
+		t.Errorf("Expected onClick to belong to the component")
+	}
+}
+
+func TestSFCParser_ParseSvelte(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "Button.svelte")
+	testContent := `<script>
+  import { createEventDispatcher } from 'svelte';
+  export let label;
+  export let count = 0;
+  export const size = 'md';
+  const dispatch = createEventDispatcher();
+  function press() { dispatch('press', count); }
+</script>
+
+<button on:click={press}><slot name="icon" />{label}</button>
+`
+	err := os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	parser := &SFCParser{Svelte: true}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(definitions) != 2 {
+		t.Fatalf("Expected 2 definitions, got %d", len(definitions))
+	}
+
+	component := definitions[0]
+	if component.Props != "{ label, count }" {
+		t.Errorf("Unexpected props %q", component.Props)
+	}
+	if len(component.Emits) != 1 || component.Emits[0] != "press" {
+		t.Errorf("Unexpected emits %v", component.Emits)
+	}
+	if len(component.Slots) != 1 || component.Slots[0] != "icon" {
+		t.Errorf("Unexpected slots %v", component.Slots)
+	}
+	if definitions[1].Name != "press" || definitions[1].Line != 7 {
+		t.Errorf("Expected press on line 7, got %s on line %d", definitions[1].Name, definitions[1].Line)
+	}
+}
+
+func TestSFCParser_ParseTSX(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "Badge.vue")
+	testContent := `<script setup lang="tsx">
+const Icon = (props: { name: string }) => <i class={props.name} />
+</script>
+`
+	if err := os.WriteFile(testFile, []byte(testContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	definitions, err := (&SFCParser{}).Parse(testFile)
+	if err != nil {
+		t.Fatalf("Expected JSX to parse in a tsx script block, got %v", err)
+	}
+	if len(definitions) != 2 || definitions[1].Name != "Icon" || definitions[1].Line != 2 {
+		t.Errorf("Expected Icon on line 2, got %+v", definitions)
+	}
+}
@@ -0,0 +1,198 @@
+package parser
+
+import (
+	"context"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/swift"
+
+	"codemap/internal/types"
+)
+
+// SwiftParser implements the Parser interface for Swift files
+type SwiftParser struct{}
+
+// Parse reads filePath and extracts its definitions with ParseContent
+func (p *SwiftParser) Parse(filePath string) ([]types.Definition, error) {
+	return parseFile(p, filePath)
+}
+
+// ParseContent extracts definitions from Swift source using AST parsing.
+// Extensions are emitted as "extension" definitions linked to the type
+// they extend, and their members are qualified by that type.
+func (p *SwiftParser) ParseContent(ctx context.Context, filePath string, src []byte) ([]types.Definition, error) {
+	parser := sitter.NewParser()
+	parser.SetLanguage(swift.GetLanguage())
+	tree, err := parser.ParseCtx(ctx, nil, src)
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []types.Definition
+
+	walkSwiftTree(tree.RootNode(), src, filePath, nil, &definitions)
+
+	linkExtensions(definitions)
+
+	return definitions, syntaxErrors(tree.RootNode(), src)
+}
+
+func walkSwiftTree(node *sitter.Node, src []byte, filePath string, parent *types.Definition, definitions *[]types.Definition) {
+	var def *types.Definition
+	switch node.Type() {
+	case "class_declaration":
+		kind := "class"
+		if k := node.ChildByFieldName("declaration_kind"); k != nil {
+			kind = k.Type()
+		}
+		def = extractSwiftDeclaration(node, kind, src, filePath)
+		if def != nil && kind == "extension" {
+			// The extension itself is not a scope: its members are
+			// qualified by the extended type but nested under the
+			// extension, which links to the type like a child
+			def.Parent = def.Name
+			def.Id = computeId(filePath, def.Name, def.Definition)
+			*definitions = append(*definitions, *def)
+			scope := *def
+			scope.Parent = ""
+			walkSwiftChildren(node, src, filePath, &scope, definitions)
+			return
+		}
+	case "protocol_declaration":
+		def = extractSwiftDeclaration(node, "protocol", src, filePath)
+	case "function_declaration", "protocol_function_declaration":
+		defType := "function"
+		if parent != nil {
+			defType = "method"
+		}
+		def = extractSwiftDeclaration(node, defType, src, filePath)
+	case "init_declaration":
+		def = extractSwiftDeclaration(node, "constructor", src, filePath)
+	case "typealias_declaration":
+		def = extractSwiftDeclaration(node, "typealias", src, filePath)
+	}
+	if def != nil {
+		linkParent(def, parent)
+		// Members are told apart by qualified name, since a protocol
+		// requirement and its implementation share a header
+		def.Id = computeId(filePath, qualifiedNameOf(*def), def.Signature+def.Definition)
+		*definitions = append(*definitions, *def)
+		if def.Type == "function" || def.Type == "method" || def.Type == "constructor" {
+			// Function bodies hold no definitions worth mapping
+			return
+		}
+		parent = def
+	}
+
+	walkSwiftChildren(node, src, filePath, parent, definitions)
+}
+
+func walkSwiftChildren(node *sitter.Node, src []byte, filePath string, parent *types.Definition, definitions *[]types.Definition) {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		walkSwiftTree(child, src, filePath, parent, definitions)
+	}
+}
+
+// extractSwiftDeclaration builds a definition for a declaration. Types are
+// reduced to their header since members are emitted separately; enums keep
+// their cases. The Id is left to the caller, which knows the qualified name.
+func extractSwiftDeclaration(node *sitter.Node, defType string, src []byte, filePath string) *types.Definition {
+	var name string
+	if defType == "constructor" {
+		name = "init"
+	} else if nameNode := node.ChildByFieldName("name"); nameNode != nil {
+		name = nameNode.Content(src)
+	}
+	if name == "" {
+		return nil
+	}
+	header := normalizeWhitespace(headerText(node, src))
+
+	def := types.Definition{
+		Type:    defType,
+		Name:    name,
+		Line:    int(node.StartPoint().Row) + 1,
+		LineEnd: int(node.EndPoint().Row) + 1,
+		Comment: extractSwiftDoc(node, src),
+	}
+	def.Modifiers, def.Annotations = swiftModifiers(node, src)
+
+	switch defType {
+	case "function", "method", "constructor":
+		def.Signature = header
+	case "enum":
+		def.Definition = header + swiftEnumCases(node, src)
+	default:
+		def.Definition = header
+	}
+	return &def
+}
+
+// swiftModifiers splits a declaration's modifiers into keywords and attributes
+func swiftModifiers(node *sitter.Node, src []byte) ([]string, []string) {
+	var modifiers, attributes []string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if child.Type() != "modifiers" {
+			continue
+		}
+		for j := 0; j < int(child.NamedChildCount()); j++ {
+			mod := child.NamedChild(j)
+			if mod.Type() == "attribute" {
+				attributes = append(attributes, normalizeWhitespace(mod.Content(src)))
+			} else if mod.Type() != "comment" && mod.Type() != "multiline_comment" {
+				modifiers = append(modifiers, mod.Content(src))
+			}
+		}
+	}
+	return modifiers, attributes
+}
+
+// swiftEnumCases renders the cases of an enum body as " { a, b }"
+func swiftEnumCases(node *sitter.Node, src []byte) string {
+	body := node.ChildByFieldName("body")
+	if body == nil {
+		return ""
+	}
+	var cases []string
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		entry := body.NamedChild(i)
+		if entry.Type() != "enum_entry" {
+			continue
+		}
+		// One entry may declare several cases, as in "case red, green"
+		for j := 0; j < int(entry.ChildCount()); j++ {
+			if entry.FieldNameForChild(j) == "name" {
+				cases = append(cases, entry.Child(j).Content(src))
+			}
+		}
+	}
+	if len(cases) == 0 {
+		return ""
+	}
+	return " { " + strings.Join(cases, ", ") + " }"
+}
+
+// extractSwiftDoc collects the /// lines or /** */ block directly above node
+func extractSwiftDoc(node *sitter.Node, src []byte) string {
+	var lines []string
+	row := node.StartPoint().Row
+	for prev := node.PrevNamedSibling(); prev != nil; prev = prev.PrevNamedSibling() {
+		if prev.EndPoint().Row+1 < row {
+			break
+		}
+		text := prev.Content(src)
+		if prev.Type() == "multiline_comment" && strings.HasPrefix(text, "/**") {
+			lines = append([]string{cleanDocBlock(text)}, lines...)
+			break
+		}
+		if prev.Type() != "comment" || !strings.HasPrefix(text, "///") {
+			break
+		}
+		lines = append([]string{strings.TrimPrefix(strings.TrimPrefix(text, "///"), " ")}, lines...)
+		row = prev.StartPoint().Row
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSwiftParser_Parse(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "Point.swift")
+	testContent := `import Foundation
+
+/// A point.
+/// Immutable.
+public struct Point: Equatable {
+    init(x: Int) {}
+    static func origin() -> Point { Point(x: 0) }
+}
+
+protocol Drawable {
+    func draw(in rect: CGRect)
+}
+
+enum Color: String { case red, green }
+
+extension Point: Drawable {
+    func draw(in rect: CGRect) {}
+}
+
+func helper(_ a: Int) throws -> Int { a }
+`
+	err := os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	parser := &SwiftParser{}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []struct {
+		name   string
+		typ    string
+		parent string
+	}{
+		{"Point", "struct", ""},
+		{"init", "constructor", "Point"},
+		{"origin", "method", "Point"},
+		{"Drawable", "protocol", ""},
+		{"draw", "method", "Drawable"},
+		{"Color", "enum", ""},
+		{"Point", "extension", "Point"},
+		{"draw", "method", "Point"},
+		{"helper", "function", ""},
+	}
+
+	if len(definitions) != len(expected) {
+		t.Fatalf("Expected %d definitions, got %d", len(expected), len(definitions))
+	}
+
+	for i, exp := range expected {
+		if definitions[i].Name != exp.name {
+			t.Errorf("Definition %d: expected name %s, got %s", i, exp.name, definitions[i].Name)
+		}
+		if definitions[i].Type != exp.typ {
+			t.Errorf("Definition %d: expected type %s, got %s", i, exp.typ, definitions[i].Type)
+		}
+		if definitions[i].Parent != exp.parent {
+			t.Errorf("Definition %d: expected parent %q, got %q", i, exp.parent, definitions[i].Parent)
+		}
+	}
+
+	if definitions[0].Comment != "A point.\nImmutable." {
+		t.Errorf("Expected doc comment, got %q", definitions[0].Comment)
+	}
+	if want := "static func origin() -> Point"; definitions[2].Signature != want {
+		t.Errorf("Expected signature %q, got %q", want, definitions[2].Signature)
+	}
+	if want := "enum Color: String { red, green }"; definitions[5].Definition != want {
+		t.Errorf("Expected enum definition %q, got %q", want, definitions[5].Definition)
+	}
+	if definitions[6].ParentId != definitions[0].Id {
+		t.Errorf("Expected extension to be linked to Point")
+	}
+	if definitions[7].ParentId != definitions[6].Id || definitions[7].Id == definitions[4].Id {
+		t.Errorf("Expected extension member to be nested under the extension with its own id")
+	}
+}
@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"context"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/typescript/tsx"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+
+	"codemap/internal/types"
+)
+
+// TSParser implements the Parser interface for TypeScript and TSX files
+type TSParser struct {
+	// TSX selects the TSX grammar, which accepts JSX elements in expressions
+	TSX bool
+}
+
+// Parse reads filePath and extracts its definitions with ParseContent
+func (p *TSParser) Parse(filePath string) ([]types.Definition, error) {
+	return parseFile(p, filePath)
+}
+
+// ParseContent extracts definitions from TS/TSX source using AST parsing. The
+// source may be embedded in filePath, such as the script block of a
+// single-file component.
+func (p *TSParser) ParseContent(ctx context.Context, filePath string, src []byte) ([]types.Definition, error) {
+	tree, err := p.parseTree(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	return p.mapTree(tree, src, filePath)
+}
+
+// parseTree parses TS or TSX source into a syntax tree
+func (p *TSParser) parseTree(ctx context.Context, src []byte) (*sitter.Tree, error) {
+	lang := typescript.GetLanguage()
+	if p.TSX {
+		lang = tsx.GetLanguage()
+	}
+	parser := sitter.NewParser()
+	parser.SetLanguage(lang)
+	return parser.ParseCtx(ctx, nil, src)
+}
+
+// mapTree extracts the definitions of a tree returned by parseTree
+func (p *TSParser) mapTree(tree *sitter.Tree, src []byte, filePath string) ([]types.Definition, error) {
+	lines := strings.Split(string(src), "\n")
+	var definitions []types.Definition
+
+	walkTree(tree.RootNode(), src, lines, filePath, extractTSNode, nil, &definitions)
+
+	return definitions, syntaxErrors(tree.RootNode(), src)
+}
+
+// extractTSNode builds definitions for the nodes the TypeScript grammar adds
+// to JavaScript's, for use as the extra extractor of walkTree
+func extractTSNode(node *sitter.Node, src []byte, lines []string, filePath string) *types.Definition {
+	switch node.Type() {
+	case "function_signature":
+		return extractFunction(node, src, lines, filePath)
+	case "abstract_class_declaration":
+		return extractClass(node, src, lines, filePath)
+	case "abstract_method_signature":
+		return extractMethod(node, src, lines, filePath)
+	case "interface_declaration":
+		return extractTSDeclaration(node, "interface", src, lines, filePath)
+	case "type_alias_declaration":
+		return extractTSDeclaration(node, "type_alias", src, lines, filePath)
+	case "enum_declaration":
+		return extractTSDeclaration(node, "enum", src, lines, filePath)
+	case "internal_module", "module":
+		return extractTSDeclaration(node, "namespace", src, lines, filePath)
+	}
+	return nil
+}
+
+// extractTSDeclaration builds a definition for TypeScript-only declarations.
+// Namespaces are reduced to their header since the body holds definitions
+// of its own.
+func extractTSDeclaration(node *sitter.Node, defType string, src []byte, lines []string, filePath string) *types.Definition {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil
+	}
+	name := strings.Trim(nameNode.Content(src), `"'`)
+	if name == "" {
+		return nil
+	}
+	definition := node.Content(src)
+	if defType == "namespace" {
+		definition = headerText(node, src)
+	}
+	line := int(node.StartPoint().Row) + 1
+	comment := extractJSComment(lines, declarationStartRow(node))
+	def := types.Definition{
+		Type:       defType,
+		Name:       name,
+		Line:       line,
+		LineEnd:    int(node.EndPoint().Row) + 1,
+		Id:         computeId(filePath, name, definition),
+		Definition: normalizeWhitespace(definition),
+		Comment:    comment,
+	}
+	return &def
+}
+
+// headerText returns the source of node up to the start of its body field
+func headerText(node *sitter.Node, src []byte) string {
+	end := node.EndByte()
+	if body := node.ChildByFieldName("body"); body != nil {
+		end = body.StartByte()
+	}
+	return strings.TrimSpace(string(src[node.StartByte():end]))
+}
@@ -0,0 +1,175 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTSParser_Parse(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "test.ts")
+	testContent := `// Shape is anything with an area
+export interface Shape {
+    area(): number;
+}
+
+type ID = string | number;
+
+export enum Color { Red, Green }
+
+namespace Geo {
+    export function distance(a: number, b: number): number {
+        return Math.abs(a - b);
+    }
+}
+
+@Injectable()
+export abstract class Base<T> implements Shape {
+    abstract area(): number;
+}
+
+declare function lookup(id: ID): Shape;
+`
+	err := os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	parser := &TSParser{}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []struct {
+		name string
+		typ  string
+	}{
+		{"Shape", "interface"},
+		{"ID", "type_alias"},
+		{"Color", "enum"},
+		{"Geo", "namespace"},
+		{"distance", "function"},
+		{"Base", "type"},
+		{"area", "method"},
+		{"lookup", "function"},
+	}
+
+	if len(definitions) != len(expected) {
+		t.Fatalf("Expected %d definitions, got %d", len(expected), len(definitions))
+	}
+
+	for i, exp := range expected {
+		if definitions[i].Name != exp.name {
+			t.Errorf("Definition %d: expected name %s, got %s", i, exp.name, definitions[i].Name)
+		}
+		if definitions[i].Type != exp.typ {
+			t.Errorf("Definition %d: expected type %s, got %s", i, exp.typ, definitions[i].Type)
+		}
+	}
+
+	if definitions[0].Comment != " Shape is anything with an area" {
+		t.Errorf("Expected interface comment, got %q", definitions[0].Comment)
+	}
+	if want := "@Injectable() abstract class Base<T> implements Shape { abstract area(): number }"; definitions[5].Definition != want {
+		t.Errorf("Expected class definition %q, got %q", want, definitions[5].Definition)
+	}
+	if want := "function distance(a: number, b: number): number"; definitions[4].Signature != want {
+		t.Errorf("Expected signature %q, got %q", want, definitions[4].Signature)
+	}
+}
+
+func TestTSParser_ParseTSX(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "test.tsx")
+	testContent := `export function Hello(props: { name: string }): JSX.Element {
+    return <div>Hello {props.name}</div>;
+}
+`
+	err := os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	parser := &TSParser{TSX: true}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(definitions) != 1 || definitions[0].Name != "Hello" {
+		t.Fatalf("Expected a single Hello definition, got %+v", definitions)
+	}
+}
+
+func TestTSParser_Components(t *testing.T) {
+	tempDir := t.TempDir()
+
+	testFile := filepath.Join(tempDir, "components.tsx")
+	testContent := `export function Foo({ a }: FooProps) {
+    const [x] = useState(0);
+    React.useEffect(() => {});
+    return <div>{a}</div>;
+}
+
+const Bar: React.FC<BarProps> = ({ b }) => <></>;
+
+export default class Page extends React.Component<PageProps, PageState> {
+    render() { return <p/>; }
+}
+
+const Memo = memo((props: MemoProps) => <span/>);
+
+function format(n: number) { return n.toString(); }
+
+export { Bar };
+`
+	err := os.WriteFile(testFile, []byte(testContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	parser := &TSParser{TSX: true}
+	definitions, err := parser.Parse(testFile)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []struct {
+		name   string
+		typ    string
+		props  string
+		export string
+	}{
+		{"Foo", "component", "FooProps", "named"},
+		{"Bar", "component", "BarProps", "named"},
+		{"Page", "component", "PageProps", "default"},
+		{"render", "method", "", ""},
+		{"Memo", "component", "MemoProps", ""},
+		{"format", "function", "", ""},
+	}
+
+	if len(definitions) != len(expected) {
+		t.Fatalf("Expected %d definitions, got %d", len(expected), len(definitions))
+	}
+
+	for i, exp := range expected {
+		def := definitions[i]
+		if def.Name != exp.name || def.Type != exp.typ {
+			t.Errorf("Definition %d: expected %s %s, got %s %s", i, exp.typ, exp.name, def.Type, def.Name)
+		}
+		if def.Props != exp.props {
+			t.Errorf("Definition %d: expected props %q, got %q", i, exp.props, def.Props)
+		}
+		if def.Export != exp.export {
+			t.Errorf("Definition %d: expected export %q, got %q", i, exp.export, def.Export)
+		}
+	}
+
+	if hooks := definitions[0].Hooks; len(hooks) != 2 || hooks[0] != "useState" || hooks[1] != "useEffect" {
+		t.Errorf("Expected useState and useEffect hooks, got %v", hooks)
+	}
+}
@@ -4,6 +4,17 @@ package types
 type Config struct {
 	Version string   `yaml:"version"`
 	Sections []Section `yaml:"sections"`
+	Extensions map[string]string `yaml:"extensions"` // Extra extensions mapped to a language name, e.g. .mjs: javascript
+	Plugins  []Plugin `yaml:"plugins"`                 // External parsers for files codemap has no parser for
+	Queries  string   `yaml:"queries"`                 // Directory of <language>.scm tags queries adding definitions
+}
+
+// Plugin registers an external parser executable for files matching a glob
+type Plugin struct {
+	Pattern  string   `yaml:"pattern"`  // Glob matched against file paths, e.g. "**/*.dsl"
+	Command  []string `yaml:"command"`  // Executable and its arguments
+	Language string   `yaml:"language"` // Language reported for matched files; defaults to the executable name
+	Timeout  string   `yaml:"timeout"`  // Time allowed per file, e.g. "5s"; defaults to 10s
 }
 
 // Section defines a named section of the codebase for mapping
@@ -24,15 +35,59 @@ type Definition struct {
 	Signature  string `json:"signature,omitempty"`  // For functions
 	Definition string `json:"definition,omitempty"` // For types
 	Comment    string `json:"comment,omitempty"`
+	Tag        string `json:"tag,omitempty" yaml:"tag,omitempty"` // Struct field tag, including its backquotes
+	Modifiers  []string `json:"modifiers,omitempty" yaml:"modifiers,omitempty"`   // Keywords such as public, static or abstract
+	Annotations []string `json:"annotations,omitempty" yaml:"annotations,omitempty"` // Annotations or attributes applied to the definition
+	Parent     string `json:"parent,omitempty" yaml:"parent,omitempty"`         // Qualified name of the enclosing definition, e.g. a method's class or receiver
+	ParentId   string `json:"parent_id,omitempty" yaml:"parent_id,omitempty"`      // Id of the enclosing definition, when it is in the same file
+	QualifiedName string `json:"qualified_name,omitempty" yaml:"qualified_name,omitempty"` // Name qualified by its parent, e.g. JSParser.Parse
+	DeclarationId    string `json:"declaration_id,omitempty" yaml:"declaration_id,omitempty"`    // Id of the prototype an implementation was declared by
+	ImplementationId string `json:"implementation_id,omitempty" yaml:"implementation_id,omitempty"` // Id of the implementation of a prototype
+	Export     string   `json:"export,omitempty" yaml:"export,omitempty"` // Export status of a JS/TS definition: default, named or commonjs
+	Props      string   `json:"props,omitempty" yaml:"props,omitempty"`  // Props type or declaration of a component
+	Hooks      []string `json:"hooks,omitempty" yaml:"hooks,omitempty"`  // Hooks called by a React component
+	Emits      []string `json:"emits,omitempty" yaml:"emits,omitempty"`  // Events emitted by a Vue or Svelte component
+	Slots      []string `json:"slots,omitempty" yaml:"slots,omitempty"`  // Slots offered by a Vue or Svelte component
+	Params     []DocParam `json:"params,omitempty" yaml:"params,omitempty"`  // Parameters documented in the doc comment
+	Returns    *DocParam  `json:"returns,omitempty" yaml:"returns,omitempty"` // Documented return value
+	Raises     []DocParam `json:"raises,omitempty" yaml:"raises,omitempty"`  // Documented exceptions raised or thrown
+	Deprecated string     `json:"deprecated,omitempty" yaml:"deprecated,omitempty"` // Deprecation notice, "deprecated" when it gives no reason
+	Examples   []string   `json:"examples,omitempty" yaml:"examples,omitempty"`   // Usage examples from the doc comment
+	TypeParams []TypeParam `json:"type_params,omitempty" yaml:"type_params,omitempty"` // Type parameters of a generic function or type
+	Build      string      `json:"build,omitempty" yaml:"build,omitempty"`       // Build constraint of the Go file, e.g. linux && amd64
+	Files      []string    `json:"files,omitempty" yaml:"files,omitempty"`       // Files making up a Go package or C# partial type
+}
+
+// TypeParam is a type parameter of a generic definition and its constraint
+type TypeParam struct {
+	Name       string `json:"name" yaml:"name"`
+	Constraint string `json:"constraint,omitempty" yaml:"constraint,omitempty"`
+}
+
+// DocParam is a structured entry parsed from a doc comment section
+type DocParam struct {
+	Name        string `json:"name,omitempty" yaml:"name,omitempty"`
+	Type        string `json:"type,omitempty" yaml:"type,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
 }
 
 // FileMap contains the parsed definitions for a single file
 type FileMap struct {
 	Path        string
 	Language    string
+	Package     string `yaml:"package,omitempty"` // Import path of the Go package the file belongs to
+	Partial     bool   `yaml:"partial,omitempty"` // Set when syntax errors left parts of the file unmapped
+	Diagnostics []Diagnostic `yaml:"diagnostics,omitempty"`
 	Definitions []Definition
 }
 
+// Diagnostic is a problem reported while parsing a file
+type Diagnostic struct {
+	Severity string `json:"severity" yaml:"severity"` // "error" for syntax errors
+	Line     int    `json:"line" yaml:"line"`
+	Message  string `json:"message" yaml:"message"`
+}
+
 // CodeMap represents the complete code map
 type CodeMap struct {
 	Files []FileMap